@@ -0,0 +1,19 @@
+// Package cache keeps todo reads out of the database. NewTodoRepository
+// wraps a todo repository, serving Fetch and GetByID from a Store and
+// dropping the affected entries when a write commits. Values are stored as
+// JSON behind a small interface so several instances can share a cache;
+// MemoryStore keeps them in process.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store holds encoded values until they expire or are deleted. Errors make
+// the repository fall back to the database, so a Store may fail open.
+type Store interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is an in-process LRU cache holding at most maxEntries
+// values. Writes on another instance do not reach it, so its entries can
+// be up to their ttl stale there; use a shared Store when running several
+// replicas.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+// NewMemoryStore returns a MemoryStore evicting the least recently used
+// value beyond maxEntries; 0 means no bound.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{maxEntries: maxEntries, order: list.New(), entries: map[string]*list.Element{}, now: time.Now}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*entry)
+	if !s.now().Before(e.expiresAt) {
+		s.remove(el)
+		return nil, false, nil
+	}
+	s.order.MoveToFront(el)
+	return e.value, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt := s.now().Add(ttl)
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := s.entries[key]; ok {
+			s.remove(el)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of values held, expired ones included.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*entry).key)
+}
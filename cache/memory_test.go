@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(2)
+	ctx := context.Background()
+	now := time.Unix(1650000000, 0)
+	s.now = func() time.Time { return now }
+
+	s.Set(ctx, "a", []byte("1"), time.Minute)
+	s.Set(ctx, "b", []byte("2"), time.Minute)
+	if v, ok, _ := s.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v, want 1", v, ok)
+	}
+	// b is now the least recently used.
+	s.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, ok, _ := s.Get(ctx, "b"); ok {
+		t.Error("Get(b) after eviction hit")
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", s.Len())
+	}
+
+	s.Set(ctx, "a", []byte("4"), time.Hour)
+	if v, _, _ := s.Get(ctx, "a"); string(v) != "4" {
+		t.Errorf("Get(a) after Set() = %q, want 4", v)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := s.Get(ctx, "c"); ok {
+		t.Error("Get(c) after expiry hit")
+	}
+	s.Delete(ctx, "a", "missing")
+	if _, ok, _ := s.Get(ctx, "a"); ok {
+		t.Error("Get(a) after Delete() hit")
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", s.Len())
+	}
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+type todoRepository struct {
+	next    usecase.TodoRepositoryInterface
+	store   Store
+	ttl     time.Duration
+	group   singleflight.Group
+	lookups *prometheus.CounterVec
+	// gen changes on every invalidation; a load that saw a write commit
+	// while it ran does not store what it read.
+	gen atomic.Uint64
+}
+
+// NewTodoRepository wraps next, caching Fetch and GetByID results in store
+// for ttl and counting lookups by method and result: hit or miss.
+// Concurrent misses for a key share one load. Reads inside a transaction
+// bypass the cache, and writes drop the tenant's list and the todo written
+// once they commit.
+func NewTodoRepository(next usecase.TodoRepositoryInterface, store Store, ttl time.Duration, reg prometheus.Registerer) usecase.TodoRepositoryInterface {
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "todo_cache_lookups_total",
+		Help: "Todo cache lookups by method and result.",
+	}, []string{"method", "result"})
+	reg.MustRegister(lookups)
+	return &todoRepository{next: next, store: store, ttl: ttl, lookups: lookups}
+}
+
+func listKey(ctx context.Context) string {
+	return "todos:" + tenant.FromContext(ctx).ID
+}
+
+func todoKey(ctx context.Context, id int64) string {
+	return fmt.Sprintf("todo:%s:%d", tenant.FromContext(ctx).ID, id)
+}
+
+// cached returns the value stored under key, or loads and stores it.
+func cached[T any](ctx context.Context, r *todoRepository, method, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if repository.InTx(ctx) {
+		return load(ctx)
+	}
+	b, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Warn("cache get", "key", key, "error", err)
+	}
+	if ok {
+		var v T
+		if err := json.Unmarshal(b, &v); err == nil {
+			r.lookups.WithLabelValues(method, "hit").Inc()
+			return v, nil
+		}
+	}
+	r.lookups.WithLabelValues(method, "miss").Inc()
+
+	v, err, _ := r.group.Do(key, func() (interface{}, error) {
+		gen := r.gen.Load()
+		v, err := load(ctx)
+		if err != nil {
+			return v, err
+		}
+		if b, err := json.Marshal(v); err == nil && r.gen.Load() == gen {
+			if err := r.store.Set(ctx, key, b, r.ttl); err != nil {
+				logging.FromContext(ctx).Warn("cache set", "key", key, "error", err)
+			}
+		}
+		return v, nil
+	})
+	return v.(T), err
+}
+
+// invalidate drops the tenant's list and the given todos once the write
+// ctx may be part of has committed.
+func (r *todoRepository) invalidate(ctx context.Context, ids ...int64) {
+	keys := []string{listKey(ctx)}
+	for _, id := range ids {
+		keys = append(keys, todoKey(ctx, id))
+	}
+	repository.AfterCommit(ctx, func() {
+		r.gen.Add(1)
+		if err := r.store.Delete(ctx, keys...); err != nil {
+			logging.FromContext(ctx).Warn("cache delete", "keys", keys, "error", err)
+		}
+	})
+}
+
+func (r *todoRepository) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	return cached(ctx, r, "Fetch", listKey(ctx), r.next.Fetch)
+}
+
+// FetchPage is not cached: invalidation cannot tell which pages a write
+// moves.
+func (r *todoRepository) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	return r.next.FetchPage(ctx, filter, limit, offset)
+}
+
+func (r *todoRepository) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	return cached(ctx, r, "GetByID", todoKey(ctx, id), func(ctx context.Context) (models.User_todo_list, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+// GetForUpdate always reads the database: it is the base of a write.
+func (r *todoRepository) GetForUpdate(ctx context.Context, id int64) (models.User_todo_list, error) {
+	return r.next.GetForUpdate(ctx, id)
+}
+
+func (r *todoRepository) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	return r.next.FetchByIDs(ctx, ids)
+}
+
+func (r *todoRepository) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	res, err := r.next.Create(ctx, todo)
+	if err == nil {
+		r.invalidate(ctx)
+	}
+	return res, err
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	res, err := r.next.Update(ctx, todo, id)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+	return res, err
+}
+
+func (r *todoRepository) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	res, err := r.next.Patch(ctx, id, todo, fields)
+	if err == nil && len(fields) > 0 {
+		r.invalidate(ctx, id)
+	}
+	return res, err
+}
+
+func (r *todoRepository) Delete(ctx context.Context, id int64) error {
+	err := r.next.Delete(ctx, id)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func newRepo(t *testing.T) (*todoRepository, *MockTodoRepositoryInterface) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	next := NewMockTodoRepositoryInterface(ctrl)
+	return NewTodoRepository(next, NewMemoryStore(0), time.Minute, prometheus.NewRegistry()).(*todoRepository), next
+}
+
+func TestTodoRepository_Reads(t *testing.T) {
+	r, next := newRepo(t)
+	ctx := context.Background()
+	todo := models.User_todo_list{ID: 1, Task_name: "Belajar", Owner: "budi"}
+
+	next.EXPECT().GetByID(gomock.Any(), int64(1)).Return(todo, nil).Times(1)
+	next.EXPECT().Fetch(gomock.Any()).Return([]models.User_todo_list{todo}, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		if got, err := r.GetByID(ctx, 1); err != nil || got != todo {
+			t.Fatalf("GetByID() = %+v, %v, want %+v", got, err, todo)
+		}
+		if got, err := r.Fetch(ctx); err != nil || len(got) != 1 || got[0] != todo {
+			t.Fatalf("Fetch() = %+v, %v", got, err)
+		}
+	}
+	if n := testutil.ToFloat64(r.lookups.WithLabelValues("GetByID", "hit")); n != 1 {
+		t.Errorf("GetByID hits = %v, want 1", n)
+	}
+	if n := testutil.ToFloat64(r.lookups.WithLabelValues("Fetch", "miss")); n != 1 {
+		t.Errorf("Fetch misses = %v, want 1", n)
+	}
+
+	// Errors are not cached, and tenants do not share entries.
+	next.EXPECT().GetByID(gomock.Any(), int64(2)).Return(models.User_todo_list{}, models.ErrNotFound).Times(2)
+	for i := 0; i < 2; i++ {
+		if _, err := r.GetByID(ctx, 2); !errors.Is(err, models.ErrNotFound) {
+			t.Fatalf("GetByID() error = %v, want %v", err, models.ErrNotFound)
+		}
+	}
+	other := tenant.NewContext(ctx, models.Tenant{ID: "acme"})
+	next.EXPECT().GetByID(gomock.Any(), int64(1)).Return(models.User_todo_list{ID: 1}, nil)
+	if got, _ := r.GetByID(other, 1); got.Task_name != "" {
+		t.Errorf("GetByID() for another tenant = %+v, served from the cache", got)
+	}
+}
+
+func TestTodoRepository_Invalidation(t *testing.T) {
+	todo := models.User_todo_list{ID: 1, Task_name: "Belajar"}
+	tests := []struct {
+		name  string
+		write func(r *todoRepository, next *MockTodoRepositoryInterface)
+		// wantFetch and wantGet count the reads missing after the write.
+		wantFetch, wantGet int
+	}{
+		{
+			name: "create drops the list",
+			write: func(r *todoRepository, next *MockTodoRepositoryInterface) {
+				next.EXPECT().Create(gomock.Any(), gomock.Any()).Return(todo, nil)
+				r.Create(context.Background(), todo)
+			},
+			wantFetch: 1,
+		},
+		{
+			name: "update drops the list and the todo",
+			write: func(r *todoRepository, next *MockTodoRepositoryInterface) {
+				next.EXPECT().Update(gomock.Any(), gomock.Any(), int64(1)).Return(todo, nil)
+				r.Update(context.Background(), todo, 1)
+			},
+			wantFetch: 1, wantGet: 1,
+		},
+		{
+			name: "patch drops the list and the todo",
+			write: func(r *todoRepository, next *MockTodoRepositoryInterface) {
+				next.EXPECT().Patch(gomock.Any(), int64(1), gomock.Any(), []string{"task_name"}).Return(todo, nil)
+				r.Patch(context.Background(), 1, todo, []string{"task_name"})
+			},
+			wantFetch: 1, wantGet: 1,
+		},
+		{
+			name: "delete drops the list and the todo",
+			write: func(r *todoRepository, next *MockTodoRepositoryInterface) {
+				next.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+				r.Delete(context.Background(), 1)
+			},
+			wantFetch: 1, wantGet: 1,
+		},
+		{
+			name: "failed write keeps entries",
+			write: func(r *todoRepository, next *MockTodoRepositoryInterface) {
+				next.EXPECT().Delete(gomock.Any(), int64(1)).Return(errors.New("some error"))
+				r.Delete(context.Background(), 1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, next := newRepo(t)
+			ctx := context.Background()
+			next.EXPECT().GetByID(gomock.Any(), int64(1)).Return(todo, nil).Times(1 + tt.wantGet)
+			next.EXPECT().Fetch(gomock.Any()).Return([]models.User_todo_list{todo}, nil).Times(1 + tt.wantFetch)
+			r.GetByID(ctx, 1)
+			r.Fetch(ctx)
+			tt.write(r, next)
+			r.GetByID(ctx, 1)
+			r.Fetch(ctx)
+		})
+	}
+}
+
+func TestTodoRepository_Transaction(t *testing.T) {
+	r, next := newRepo(t)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	tx := repository.NewTxManager(db, sql.LevelDefault, 1)
+	ctx := context.Background()
+	todo := models.User_todo_list{ID: 1, Task_name: "Belajar"}
+
+	next.EXPECT().GetByID(gomock.Any(), int64(1)).Return(todo, nil).Times(3)
+	r.GetByID(ctx, 1)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Reads in a transaction go to the database.
+		r.GetByID(ctx, 1)
+		next.EXPECT().Update(gomock.Any(), gomock.Any(), int64(1)).Return(todo, nil)
+		r.Update(ctx, todo, 1)
+		if _, ok, _ := r.store.Get(ctx, todoKey(ctx, 1)); !ok {
+			t.Error("entry dropped before commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.GetByID(ctx, 1)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestTodoRepository_CollapsesMisses(t *testing.T) {
+	r, next := newRepo(t)
+	release := make(chan struct{})
+	next.EXPECT().Fetch(gomock.Any()).DoAndReturn(func(context.Context) ([]models.User_todo_list, error) {
+		<-release
+		return []models.User_todo_list{{ID: 1}}, nil
+	}).Times(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := r.Fetch(context.Background()); err != nil || len(got) != 1 {
+				t.Errorf("Fetch() = %+v, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
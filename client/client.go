@@ -0,0 +1,229 @@
+// Package client calls the todo REST API under /v2. Its methods mirror
+// handler.TodoUsecaseInterface and return the same models errors, so code
+// written against the usecase can run against a remote server.
+//
+// Requests that fail on the network or with 429, 502, 503 or 504 are sent
+// again with exponential backoff. Creates carry an Idempotency-Key, so a
+// retried create whose first response was lost does not add a second todo.
+// A retried delete whose first response was lost returns models.ErrNotFound.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiKeyHeader matches handler.APIKeyHeader; it is repeated so the client
+// does not pull in the server's dependencies.
+const apiKeyHeader = "X-API-Key"
+
+// Retry says how often and how long apart failed requests are sent.
+type Retry struct {
+	// MaxAttempts counts the first one; 1 disables retries.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay. A server asking, with Retry-After, to
+	// wait longer than this gets its error returned instead.
+	MaxBackoff time.Duration
+}
+
+var DefaultRetry = Retry{MaxAttempts: 3, BaseBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// backoff returns the delay before the given (1-based) retry.
+func (r Retry) backoff(attempt int) time.Duration {
+	delay := r.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= r.MaxBackoff {
+			return r.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Client talks to one server. It is safe for concurrent use. The zero value
+// is not usable; use New.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	retry        Retry
+	apiKey       string
+	tenant       string
+	tenantHeader string
+}
+
+type Option func(*Client)
+
+// WithAPIKey authenticates every request with key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithTenant selects tenant id through header, which defaults to
+// X-Tenant-ID when empty.
+func WithTenant(id, header string) Option {
+	return func(c *Client) {
+		if header == "" {
+			header = "X-Tenant-ID"
+		}
+		c.tenant, c.tenantHeader = id, header
+	}
+}
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetry replaces DefaultRetry.
+func WithRetry(r Retry) Option {
+	return func(c *Client) {
+		if r.MaxAttempts < 1 {
+			r.MaxAttempts = 1
+		}
+		c.retry = r
+	}
+}
+
+// New returns a client for the server at baseURL, e.g.
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetry,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// do sends body as JSON, retrying as configured, and decodes the response
+// into out when out is not nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	header := http.Header{}
+	if method == http.MethodPost {
+		key, err := idempotencyKey()
+		if err != nil {
+			return err
+		}
+		header.Set("Idempotency-Key", key)
+	}
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, method, path, header, payload, out)
+		if err == nil || attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		delay := c.retry.backoff(attempt)
+		var (
+			apiErr *Error
+			urlErr *url.Error
+		)
+		switch {
+		case errors.As(err, &apiErr) && apiErr.temporary():
+			if apiErr.RetryAfter > c.retry.MaxBackoff {
+				return err
+			}
+			if apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+		case errors.As(err, &urlErr):
+		default:
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, payload []byte, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+	if c.tenant != "" {
+		req.Header.Set(c.tenantHeader, c.tenant)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		if json.Unmarshal(b, &e) != nil {
+			e.Error = strings.TrimSpace(string(b))
+		}
+		return &Error{Status: resp.StatusCode, Message: e.Error, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("membaca respons: %w", err)
+	}
+	return nil
+}
+
+// retryAfter parses a Retry-After value in seconds or as an HTTP date.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func idempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
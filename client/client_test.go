@@ -0,0 +1,395 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_handler "github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/idempotency"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryTodos is a TodoUsecaseInterface keeping todos in a map. err, when
+// set, is returned by every call.
+type memoryTodos struct {
+	mu     sync.Mutex
+	todos  map[int64]models.User_todo_list
+	nextID int64
+	err    error
+}
+
+func (m *memoryTodos) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var res []models.User_todo_list
+	for _, todo := range m.todos {
+		res = append(res, todo)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (m *memoryTodos) FetchPage(ctx context.Context, _ models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	todos, err := m.Fetch(ctx)
+	page := models.Todo_page{Total: len(todos)}
+	if err != nil || offset >= len(todos) {
+		return page, err
+	}
+	todos = todos[offset:]
+	if limit > 0 && limit < len(todos) {
+		todos = todos[:limit]
+	}
+	page.Todos = todos
+	return page, nil
+}
+
+func (m *memoryTodos) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	todo, ok := m.todos[id]
+	if m.err != nil || !ok {
+		return models.User_todo_list{}, firstErr(m.err, models.ErrNotFound)
+	}
+	return todo, nil
+}
+
+func (m *memoryTodos) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryTodos) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil || strings.TrimSpace(todo.Task_name) == "" {
+		return models.User_todo_list{}, firstErr(m.err, models.ErrInvalidTask)
+	}
+	m.nextID++
+	todo.ID = m.nextID
+	m.todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (m *memoryTodos) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.todos[id]; m.err != nil || !ok {
+		return models.User_todo_list{}, firstErr(m.err, models.ErrNotFound)
+	}
+	todo.ID = id
+	m.todos[id] = todo
+	return todo, nil
+}
+
+func (m *memoryTodos) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	return m.Update(ctx, todo, id)
+}
+
+func (m *memoryTodos) Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	original, err := m.GetByID(ctx, id)
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	todo, fields, err := apply(original)
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	return m.Patch(ctx, id, todo, fields)
+}
+
+func (m *memoryTodos) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.todos[id]; m.err != nil || !ok {
+		return firstErr(m.err, models.ErrNotFound)
+	}
+	delete(m.todos, id)
+	return nil
+}
+
+func (m *memoryTodos) Watch(ctx context.Context) <-chan models.Todo_event {
+	return nil
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flaky sits in front of the API. Each queued fault answers one request
+// in its place, or, when lose is set, lets the API handle it and replaces
+// the response.
+type flaky struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	faults   []fault
+	requests []*http.Request
+}
+
+type fault struct {
+	status     int
+	retryAfter string
+	lose       bool
+}
+
+func (f *flaky) queue(faults ...fault) {
+	f.mu.Lock()
+	f.faults = append(f.faults, faults...)
+	f.mu.Unlock()
+}
+
+func (f *flaky) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+func (f *flaky) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	var ft *fault
+	if len(f.faults) > 0 {
+		ft = &f.faults[0]
+		f.faults = f.faults[1:]
+	}
+	f.mu.Unlock()
+
+	if ft == nil {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	if ft.lose {
+		f.next.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if ft.retryAfter != "" {
+		w.Header().Set("Retry-After", ft.retryAfter)
+	}
+	http.Error(w, `{"error":"tidak tersedia"}`, ft.status)
+}
+
+var fastRetry = Retry{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+// newServer serves the real v2 todo handler, behind the idempotency
+// middleware, over todos with the given tasks.
+func newServer(t *testing.T, tasks ...string) (*memoryTodos, *flaky, *Client) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	store := &memoryTodos{todos: map[int64]models.User_todo_list{}}
+	for _, task := range tasks {
+		store.Create(context.Background(), models.User_todo_list{Task_name: task})
+	}
+	r := gin.New()
+	r.Use(idempotency.Middleware(idempotency.NewMemoryStore(), time.Minute, 1<<20, _handler.ClientKey))
+	_handler.NewTodoHandlerV2(r.Group("/v2"), store)
+	f := &flaky{next: r}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return store, f, New(srv.URL+"/", WithAPIKey("todo_abc_123"), WithTenant("acme", ""), WithRetry(fastRetry))
+}
+
+func TestClient(t *testing.T) {
+	store, f, c := newServer(t, "beli susu")
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, models.User_todo_list{Task_name: "cuci mobil"})
+	if err != nil || created.ID != 2 || created.Task_name != "cuci mobil" {
+		t.Fatalf("Create = %+v, %v", created, err)
+	}
+	got, err := c.GetByID(ctx, 2)
+	if err != nil || got != created {
+		t.Errorf("GetByID = %+v, %v", got, err)
+	}
+	updated, err := c.Update(ctx, models.User_todo_list{Task_name: "cuci motor"}, 2)
+	if err != nil || updated.Task_name != "cuci motor" || store.todos[2].Task_name != "cuci motor" {
+		t.Errorf("Update = %+v, %v", updated, err)
+	}
+	patched, err := c.Patch(ctx, 2, models.User_todo_list{Task_name: "diabaikan", Done: true}, []string{"done"})
+	if err != nil || !patched.Done || patched.Task_name != "cuci motor" {
+		t.Errorf("Patch = %+v, %v", patched, err)
+	}
+	todos, err := c.Fetch(ctx)
+	if err != nil || len(todos) != 2 {
+		t.Errorf("Fetch = %+v, %v", todos, err)
+	}
+	if err := c.Delete(ctx, 1); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if _, err := c.GetByID(ctx, 1); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetByID of a deleted todo = %v, want ErrNotFound", err)
+	}
+
+	for _, r := range f.requests {
+		if r.Header.Get("X-API-Key") != "todo_abc_123" || r.Header.Get("X-Tenant-ID") != "acme" {
+			t.Errorf("%s %s sent headers %v", r.Method, r.URL, r.Header)
+		}
+		if got := r.Header.Get("Idempotency-Key") != ""; got != (r.Method == http.MethodPost) {
+			t.Errorf("%s %s Idempotency-Key set = %v", r.Method, r.URL, got)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		call       func(c *Client) error
+		wantStatus int
+		want       error
+	}{
+		{
+			name: "invalid task",
+			call: func(c *Client) error {
+				_, err := c.Create(context.Background(), models.User_todo_list{Task_name: " "})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+			want:       ErrBadRequest,
+		},
+		{
+			name:       "not found",
+			call:       func(c *Client) error { return c.Delete(context.Background(), 9) },
+			wantStatus: http.StatusNotFound,
+			want:       models.ErrNotFound,
+		},
+		{
+			name:       "forbidden",
+			err:        models.ErrForbidden,
+			call:       func(c *Client) error { return c.Delete(context.Background(), 1) },
+			wantStatus: http.StatusForbidden,
+			want:       models.ErrForbidden,
+		},
+		{
+			name: "server",
+			err:  errors.New("connection refused"),
+			call: func(c *Client) error {
+				_, err := c.Fetch(context.Background())
+				return err
+			},
+			wantStatus: http.StatusInternalServerError,
+			want:       ErrServer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, f, c := newServer(t, "beli susu")
+			store.err = tt.err
+			err := tt.call(c)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Status != tt.wantStatus || !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want status %d and %v", err, tt.wantStatus, tt.want)
+			}
+			if f.count() != 1 {
+				t.Errorf("sent %d requests, want no retries", f.count())
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("until success", func(t *testing.T) {
+		_, f, c := newServer(t, "beli susu")
+		f.queue(fault{status: http.StatusServiceUnavailable}, fault{status: http.StatusTooManyRequests, retryAfter: "0"})
+		todos, err := c.Fetch(context.Background())
+		if err != nil || len(todos) != 1 || f.count() != 3 {
+			t.Errorf("Fetch = %v, %v after %d requests", todos, err, f.count())
+		}
+	})
+	t.Run("gives up", func(t *testing.T) {
+		_, f, c := newServer(t)
+		f.queue(fault{status: http.StatusBadGateway}, fault{status: http.StatusBadGateway}, fault{status: http.StatusBadGateway})
+		_, err := c.Fetch(context.Background())
+		if !errors.Is(err, ErrServer) || f.count() != 3 {
+			t.Errorf("err = %v after %d requests, want ErrServer after 3", err, f.count())
+		}
+	})
+	t.Run("long Retry-After", func(t *testing.T) {
+		_, f, c := newServer(t)
+		f.queue(fault{status: http.StatusTooManyRequests, retryAfter: "3600"})
+		_, err := c.Fetch(context.Background())
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Hour || f.count() != 1 {
+			t.Errorf("err = %v after %d requests, want the 429 at once", err, f.count())
+		}
+	})
+	t.Run("lost create", func(t *testing.T) {
+		store, f, c := newServer(t)
+		f.queue(fault{status: http.StatusServiceUnavailable, lose: true})
+		todo, err := c.Create(context.Background(), models.User_todo_list{Task_name: "beli susu"})
+		if err != nil || todo.ID != 1 {
+			t.Fatalf("Create = %+v, %v", todo, err)
+		}
+		if len(store.todos) != 1 {
+			t.Errorf("retried create stored %d todos, want 1", len(store.todos))
+		}
+		if f.requests[0].Header.Get("Idempotency-Key") != f.requests[1].Header.Get("Idempotency-Key") {
+			t.Error("retry sent another Idempotency-Key")
+		}
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		_, f, c := newServer(t)
+		c.retry.BaseBackoff, c.retry.MaxBackoff = time.Hour, time.Hour
+		f.queue(fault{status: http.StatusServiceUnavailable})
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := c.Fetch(ctx); !errors.Is(err, ErrServer) || f.count() != 1 {
+			t.Errorf("err = %v after %d requests, want the 503 once ctx is done", err, f.count())
+		}
+	})
+}
+
+func TestPages(t *testing.T) {
+	_, f, c := newServer(t, "a", "b", "c", "d", "e")
+	ctx := context.Background()
+
+	page, err := c.FetchPage(ctx, Limit(2), Offset(2))
+	if err != nil || len(page.Todos) != 2 || page.Todos[0].Task_name != "c" || page.TotalCount != 5 || page.NextOffset != 4 {
+		t.Errorf("FetchPage = %+v, %v", page, err)
+	}
+
+	f.requests = nil
+	var tasks []string
+	for todo, err := range c.All(ctx, 2) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, todo.Task_name)
+	}
+	if strings.Join(tasks, "") != "abcde" || f.count() != 3 {
+		t.Errorf("All yielded %v in %d requests", tasks, f.count())
+	}
+
+	f.requests = nil
+	for range c.All(ctx, 2) {
+		break
+	}
+	if f.count() != 1 {
+		t.Errorf("stopping early still sent %d requests", f.count())
+	}
+
+	f.queue(fault{status: http.StatusBadRequest})
+	n := 0
+	for _, err := range c.All(ctx, 2) {
+		n++
+		if !errors.Is(err, ErrBadRequest) {
+			t.Errorf("All yielded %v, want the error", err)
+		}
+	}
+	if n != 1 {
+		t.Errorf("All yielded %d times after an error, want once", n)
+	}
+}
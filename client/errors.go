@@ -0,0 +1,68 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// errors for statuses without a models counterpart; see Error.Unwrap
+var (
+	ErrBadRequest  = errors.New("permintaan tidak valid")
+	ErrConflict    = errors.New("permintaan bentrok")
+	ErrRateLimited = errors.New("terlalu banyak permintaan")
+	ErrServer      = errors.New("kesalahan server")
+)
+
+// Error is a response with an error status. It unwraps to the models error
+// the server mapped onto the status, or to one of the errors above, so
+// callers can test it with errors.Is(err, models.ErrNotFound).
+type Error struct {
+	Status  int
+	Message string
+	// RetryAfter is the Retry-After of the response, if any.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("status %d", e.Status)
+	}
+	return fmt.Sprintf("status %d: %s", e.Status, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch {
+	case e.Status == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.Status == http.StatusUnauthorized:
+		return models.ErrUnauthorized
+	case e.Status == http.StatusForbidden:
+		return models.ErrForbidden
+	case e.Status == http.StatusNotFound:
+		return models.ErrNotFound
+	case e.Status == http.StatusConflict:
+		return ErrConflict
+	case e.Status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.Status >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// temporary reports whether the request may succeed when sent again. A
+// conflict is only temporary with a Retry-After, which the server sends
+// while a request with the same Idempotency-Key is still running.
+func (e *Error) temporary() bool {
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return e.RetryAfter > 0
+	}
+	return false
+}
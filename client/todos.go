@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// DefaultPageSize is the page size of All when it is given none.
+const DefaultPageSize = 100
+
+// FetchOption narrows a fetch to one page.
+type FetchOption func(q url.Values)
+
+// Limit returns at most n todos.
+func Limit(n int) FetchOption {
+	return func(q url.Values) { q.Set("limit", strconv.Itoa(n)) }
+}
+
+// Offset skips the first n todos.
+func Offset(n int) FetchOption {
+	return func(q url.Values) { q.Set("offset", strconv.Itoa(n)) }
+}
+
+// Page is one page of todos.
+type Page struct {
+	Todos      []models.User_todo_list
+	TotalCount int
+	// NextOffset is the offset of the next page, or 0 on the last one.
+	NextOffset int
+}
+
+type todoResponse struct {
+	Data models.User_todo_list `json:"data"`
+}
+
+// FetchPage returns the todos opts select, every one without options.
+func (c *Client) FetchPage(ctx context.Context, opts ...FetchOption) (Page, error) {
+	q := url.Values{}
+	for _, opt := range opts {
+		opt(q)
+	}
+	path := "/v2/todos"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	var res struct {
+		Data       []models.User_todo_list `json:"data"`
+		TotalCount int                     `json:"total_count"`
+		NextOffset int                     `json:"next_offset"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return Page{}, err
+	}
+	return Page{Todos: res.Data, TotalCount: res.TotalCount, NextOffset: res.NextOffset}, nil
+}
+
+// Fetch returns the todos opts select, every one without options.
+func (c *Client) Fetch(ctx context.Context, opts ...FetchOption) ([]models.User_todo_list, error) {
+	page, err := c.FetchPage(ctx, opts...)
+	return page.Todos, err
+}
+
+// All iterates over every todo, fetching pageSize at a time. It stops after
+// yielding the first error. Todos created or deleted while it runs may
+// shift the pages, so one can be skipped or seen twice.
+func (c *Client) All(ctx context.Context, pageSize int) iter.Seq2[models.User_todo_list, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return func(yield func(models.User_todo_list, error) bool) {
+		offset := 0
+		for {
+			page, err := c.FetchPage(ctx, Limit(pageSize), Offset(offset))
+			if err != nil {
+				yield(models.User_todo_list{}, err)
+				return
+			}
+			for _, todo := range page.Todos {
+				if !yield(todo, nil) {
+					return
+				}
+			}
+			if page.NextOffset == 0 {
+				return
+			}
+			offset = page.NextOffset
+		}
+	}
+}
+
+func (c *Client) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	var res todoResponse
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/todos/%d", id), nil, &res)
+	return res.Data, err
+}
+
+func (c *Client) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	var res todoResponse
+	err := c.do(ctx, http.MethodPost, "/v2/todos", todo, &res)
+	return res.Data, err
+}
+
+// Update replaces the todo and returns it as stored.
+func (c *Client) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	var res todoResponse
+	err := c.do(ctx, http.MethodPut, fmt.Sprintf("/v2/todos/%d", id), todo, &res)
+	return res.Data, err
+}
+
+// Patch writes only the named fields of todo, given by their JSON names, and
+// returns the todo as stored.
+func (c *Client) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	raw, err := json.Marshal(todo)
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return models.User_todo_list{}, err
+	}
+	patch := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		v, ok := values[f]
+		if !ok {
+			return models.User_todo_list{}, fmt.Errorf("field %q tidak dikenal", f)
+		}
+		patch[f] = v
+	}
+	var res todoResponse
+	err = c.do(ctx, http.MethodPatch, fmt.Sprintf("/v2/todos/%d", id), patch, &res)
+	return res.Data, err
+}
+
+func (c *Client) Delete(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v2/todos/%d", id), nil, nil)
+}
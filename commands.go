@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/client"
+	"github.com/KennyKur/CRUD_Todo/config"
+	"github.com/KennyKur/CRUD_Todo/migrations"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/spf13/cobra"
+)
+
+// exit codes, for scripts
+const (
+	exitError        = 1
+	exitUsage        = 2
+	exitNotFound     = 3
+	exitInvalid      = 4
+	exitUnauthorized = 5
+)
+
+// usageError is a mistake in the command line rather than a failure.
+type usageError struct{ error }
+
+func (e usageError) Unwrap() error { return e.error }
+
+// args wraps a cobra argument check so its errors are usage errors.
+func args(check cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, a []string) error {
+		if err := check(cmd, a); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+}
+
+// exitCode maps the error of a command onto the status the binary exits
+// with.
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, models.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, models.ErrInvalidTask), errors.Is(err, models.ErrInvalidShare),
+		errors.Is(err, client.ErrBadRequest):
+		return exitInvalid
+	case errors.Is(err, models.ErrUnauthorized), errors.Is(err, models.ErrForbidden):
+		return exitUnauthorized
+	default:
+		return exitError
+	}
+}
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func registerOutput(cmd *cobra.Command, output *string) {
+	cmd.PersistentFlags().StringVarP(output, "output", "o", outputTable, "output format: table or json")
+	cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(
+		[]string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if *output != outputTable && *output != outputJSON {
+			return usageError{fmt.Errorf("format %q tidak dikenal", *output)}
+		}
+		return nil
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// openDB connects to the primary database of cfg.
+func openDB(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	db, err := repository.OpenDB(cfg.Database, cfg.Database.Host, cfg.Database.Port)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// newRootCmd builds the command line. Without a subcommand it serves, as
+// the binary did before it had any.
+func newRootCmd() *cobra.Command {
+	var configFile string
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP and gRPC servers",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			loader := config.NewLoader(configFile)
+			cfg, err := loader.Load()
+			if err != nil {
+				return err
+			}
+			serve(loader, cfg)
+			return nil
+		},
+	}
+	root := &cobra.Command{
+		Use:   "todo",
+		Short: "Todo service",
+		Long: "Todo service.\n\nSettings are read from the config file, then from " + config.EnvPrefix +
+			"_* environment variables\n(" + config.EnvPrefix + "_DATABASE_PASS for database.pass), then from the files named by\n" +
+			config.EnvPrefix + "_*_FILE variables.\n\n" +
+			"Exit status is 0 on success, 1 on failure, 2 for a wrong command line,\n" +
+			"3 when a todo is not found, 4 when it is invalid and 5 when access is denied.",
+		Args:         args(cobra.NoArgs),
+		SilenceUsage: true,
+		RunE:         serveCmd.RunE,
+	}
+	root.PersistentFlags().StringVar(&configFile, "config", "",
+		"config file (default "+config.DefaultFile+" in the working directory, if present)")
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+	root.AddCommand(serveCmd, newConfigCmd(&configFile), newMigrateCmd(&configFile),
+		newTodoCmd(&configFile), newImportCmd(&configFile), newExportCmd(&configFile),
+		newUserCmd(&configFile), newTUICmd())
+	return root
+}
+
+func newConfigCmd(configFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration and print the effective settings with secrets redacted",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			loader := config.NewLoader(*configFile)
+			if _, err := loader.Load(); err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(loader.Settings(), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return nil
+		},
+	})
+	return cmd
+}
+
+// withDB loads the configuration and connects for the duration of fn.
+func withDB(cmd *cobra.Command, configFile string, fn func(ctx context.Context, cfg *config.Config, db *sql.DB) error) error {
+	cfg, err := config.NewLoader(configFile).Load()
+	if err != nil {
+		return err
+	}
+	db, err := openDB(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return fn(cmd.Context(), cfg, db)
+}
+
+func newMigrateCmd(configFile *string) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or inspect the database migrations",
+	}
+	registerOutput(cmd, &output)
+
+	var check bool
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "List the migrations and whether they are applied",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withDB(cmd, *configFile, func(ctx context.Context, _ *config.Config, db *sql.DB) error {
+				applied, err := migrations.Applied(ctx, db)
+				if err != nil {
+					return err
+				}
+				type row struct {
+					Version string `json:"version"`
+					Applied bool   `json:"applied"`
+				}
+				rows := []row{}
+				pending := 0
+				for _, v := range migrations.Versions() {
+					rows = append(rows, row{v, applied[v]})
+					if !applied[v] {
+						pending++
+					}
+				}
+				if output == outputJSON {
+					err = writeJSON(cmd.OutOrStdout(), rows)
+				} else {
+					tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+					fmt.Fprintln(tw, "VERSION\tSTATUS")
+					for _, r := range rows {
+						state := "pending"
+						if r.Applied {
+							state = "applied"
+						}
+						fmt.Fprintf(tw, "%s\t%s\n", r.Version, state)
+					}
+					err = tw.Flush()
+				}
+				if err == nil && check && pending > 0 {
+					err = fmt.Errorf("%d migrasi belum diterapkan", pending)
+				}
+				return err
+			})
+		},
+	}
+	status.Flags().BoolVar(&check, "check", false, "fail when a migration is pending")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "Apply the pending migrations and list them",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withDB(cmd, *configFile, func(ctx context.Context, _ *config.Config, db *sql.DB) error {
+				applied, err := migrations.Up(ctx, db)
+				if err != nil {
+					return err
+				}
+				if output == outputJSON {
+					if applied == nil {
+						applied = []string{}
+					}
+					return writeJSON(cmd.OutOrStdout(), applied)
+				}
+				for _, v := range applied {
+					fmt.Fprintln(cmd.OutOrStdout(), v)
+				}
+				return nil
+			})
+		},
+	}, status)
+	return cmd
+}
+
+func newUserCmd(configFile *string) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users in the database",
+	}
+	registerOutput(cmd, &output)
+
+	var (
+		tenantID string
+		keyName  string
+		scopes   []string
+		expires  time.Duration
+	)
+	create := &cobra.Command{
+		Use:   "create USER",
+		Short: "Create an API key acting as USER and print it",
+		Long: "Create an API key acting as USER and print it. Users exist through their\n" +
+			"keys and shares; the key is shown only once.",
+		Args: args(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, a []string) error {
+			return withDB(cmd, *configFile, func(ctx context.Context, cfg *config.Config, db *sql.DB) error {
+				tn, ok := tenant.NewRegistry(cfg.Tenancy.Tenants).Lookup(tenantID)
+				if !ok {
+					return usageError{fmt.Errorf("tenant %q tidak dikenal", tenantID)}
+				}
+				name := keyName
+				if name == "" {
+					name = a[0]
+				}
+				key := models.Api_key{Name: name, User: a[0], Scopes: scopes}
+				if expires > 0 {
+					at := time.Now().Add(expires)
+					key.Expires_at = &at
+				}
+				key, err := usecase.NewApiKeyUsecase(repository.NewApiKeyRepository(db)).
+					Create(tenant.NewContext(ctx, tn), key)
+				if err != nil {
+					return err
+				}
+				if output == outputJSON {
+					return writeJSON(cmd.OutOrStdout(), key)
+				}
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "ID\tUSER\tTENANT\tSCOPES\tKEY")
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", key.ID, key.User, key.Tenant, strings.Join(key.Scopes, ","), key.Key)
+				return tw.Flush()
+			})
+		},
+	}
+	create.Flags().StringVar(&tenantID, "tenant", tenant.DefaultID, "tenant the user belongs to")
+	create.Flags().StringVar(&keyName, "name", "", "name of the key (default USER)")
+	create.Flags().StringSliceVar(&scopes, "scopes",
+		[]string{models.ScopeTodosRead, models.ScopeTodosWrite, models.ScopeTodosDelete}, "scopes of the key")
+	create.Flags().DurationVar(&expires, "expires", 0, "lifetime of the key, e.g. 720h; it never expires when 0")
+	create.RegisterFlagCompletionFunc("scopes", cobra.FixedCompletions(models.Scopes, cobra.ShellCompDirectiveNoFileComp))
+	cmd.AddCommand(create)
+	return cmd
+}
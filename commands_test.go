@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	_handler "github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryTodos is a TodoUsecaseInterface keeping todos in a map.
+type memoryTodos struct {
+	mu     sync.Mutex
+	todos  map[int64]models.User_todo_list
+	nextID int64
+}
+
+func (m *memoryTodos) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := []models.User_todo_list{}
+	for _, todo := range m.todos {
+		res = append(res, todo)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (m *memoryTodos) FetchPage(ctx context.Context, _ models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	todos, err := m.Fetch(ctx)
+	page := models.Todo_page{Total: len(todos)}
+	if err != nil || offset >= len(todos) {
+		return page, err
+	}
+	todos = todos[offset:]
+	if limit > 0 && limit < len(todos) {
+		todos = todos[:limit]
+	}
+	page.Todos = todos
+	return page, nil
+}
+
+func (m *memoryTodos) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	todo, ok := m.todos[id]
+	if !ok {
+		return models.User_todo_list{}, models.ErrNotFound
+	}
+	return todo, nil
+}
+
+func (m *memoryTodos) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryTodos) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	if strings.TrimSpace(todo.Task_name) == "" {
+		return models.User_todo_list{}, models.ErrInvalidTask
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	todo.ID = m.nextID
+	m.todos[todo.ID] = todo
+	return todo, nil
+}
+
+func (m *memoryTodos) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.todos[id]; !ok {
+		return models.User_todo_list{}, models.ErrNotFound
+	}
+	todo.ID = id
+	m.todos[id] = todo
+	return todo, nil
+}
+
+func (m *memoryTodos) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	return m.Update(ctx, todo, id)
+}
+
+func (m *memoryTodos) Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	original, err := m.GetByID(ctx, id)
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	todo, fields, err := apply(original)
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	return m.Patch(ctx, id, todo, fields)
+}
+
+func (m *memoryTodos) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.todos[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(m.todos, id)
+	return nil
+}
+
+func (m *memoryTodos) Watch(ctx context.Context) <-chan models.Todo_event {
+	return nil
+}
+
+// newAPI serves the v2 todo routes over todos.
+func newAPI(t *testing.T, todos ...string) (*memoryTodos, string) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryTodos{todos: map[int64]models.User_todo_list{}}
+	for _, task := range todos {
+		store.Create(context.Background(), models.User_todo_list{Task_name: task})
+	}
+	r := gin.New()
+	_handler.NewTodoHandlerV2(r.Group("/v2"), store)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return store, srv.URL
+}
+
+func execute(stdin string, args ...string) (string, error) {
+	cmd := newRootCmd()
+	var out bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestTodoCommands(t *testing.T) {
+	store, url := newAPI(t, "beli susu")
+
+	out, err := execute("", "todo", "add", "--api", url, "cuci", "mobil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "cuci mobil") {
+		t.Errorf("add printed %q", out)
+	}
+
+	out, err = execute("", "todo", "edit", "--api", url, "-o", "json", "1", "beli", "roti")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edited models.User_todo_list
+	if err := json.Unmarshal([]byte(out), &edited); err != nil || edited.Task_name != "beli roti" {
+		t.Errorf("edit printed %q (%v)", out, err)
+	}
+
+	out, err = execute("", "todo", "list", "--api", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[2], "cuci mobil") {
+		t.Errorf("list printed %q", out)
+	}
+
+	out, err = execute("", "todo", "done", "--api", url, "-o", "json", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var done []models.User_todo_list
+	if err := json.Unmarshal([]byte(out), &done); err != nil || len(done) != 1 || done[0].Task_name != "beli roti" || !done[0].Done {
+		t.Errorf("done printed %q (%v)", out, err)
+	}
+	if todo, ok := store.todos[1]; !ok || !todo.Done || todo.Task_name != "beli roti" {
+		t.Errorf("done left todo 1 as %+v (kept %v)", todo, ok)
+	}
+
+	if _, err := execute("", "todo", "rm", "--api", url, "2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.todos) != 1 {
+		t.Errorf("todos left: %v", store.todos)
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	src, from := newAPI(t, "satu", "dua")
+	src.Patch(context.Background(), 2, models.User_todo_list{Task_name: "dua", Done: true}, []string{"done"})
+	to, url := newAPI(t)
+
+	file := filepath.Join(t.TempDir(), "todos.json")
+	if _, err := execute("", "export", "--api", from, "--file", file); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute(string(b), "import", "--api", url); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := to.Fetch(context.Background())
+	if len(got) != 2 || got[0].Task_name != "satu" || got[0].Done || got[1].Task_name != "dua" || !got[1].Done {
+		t.Errorf("imported %v", got)
+	}
+
+	_, err = execute(`[{"task_name":"tiga"},{"task_name":""}]`, "import", "--api", url)
+	if code := exitCode(err); code != exitInvalid {
+		t.Errorf("exit code = %d, want %d (%v)", code, exitInvalid, err)
+	}
+	if got, _ := to.Fetch(context.Background()); len(got) != 3 {
+		t.Errorf("todos before the invalid one should be kept, got %v", got)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	_, url := newAPI(t)
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"todo", "list", "--api", url}, 0},
+		{[]string{"todo", "rm", "--api", url, "9"}, exitNotFound},
+		{[]string{"todo", "add", "--api", url, " "}, exitInvalid},
+		{[]string{"todo", "rm", "--api", url, "x"}, exitUsage},
+		{[]string{"todo", "rm", "--api", url}, exitUsage},
+		{[]string{"todo", "list", "--api", url, "--bogus"}, exitUsage},
+		{[]string{"todo", "list", "--api", url, "-o", "xml"}, exitUsage},
+		{[]string{"todo", "list", "--api", url, "--user", "budi"}, exitUsage},
+		{[]string{"serve", "extra"}, exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args[:2], " "), func(t *testing.T) {
+			_, err := execute("", tt.args...)
+			got := 0
+			if err != nil {
+				got = exitCode(err)
+			}
+			if got != tt.want {
+				t.Errorf("%v: exit code = %d, want %d (%v)", tt.args, got, tt.want, err)
+			}
+		})
+	}
+	if got := exitCode(fmt.Errorf("todo 1: %w", models.ErrForbidden)); got != exitUnauthorized {
+		t.Errorf("forbidden: exit code = %d, want %d", got, exitUnauthorized)
+	}
+}
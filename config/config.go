@@ -0,0 +1,259 @@
+// Package config loads the service configuration. Settings come from, in
+// increasing precedence: built-in defaults, a JSON file, TODO_* environment
+// variables, and files named by TODO_*_FILE variables for secrets.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/ratelimit"
+	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tracing"
+)
+
+// Config is the whole configuration. Durations are in seconds unless the
+// key says otherwise.
+type Config struct {
+	Debug       bool
+	Server      Server
+	GRPC        GRPC `mapstructure:"grpc"`
+	Context     Context
+	Database    repository.DBConfig
+	Cache       Cache
+	Health      Health
+	GraphQL     GraphQL `mapstructure:"graphql"`
+	Webhook     Webhook
+	Auth        Auth
+	Idempotency Idempotency
+	Tenancy     tenant.Config
+	RateLimit   RateLimit `mapstructure:"ratelimit"`
+	Tracing     tracing.Config
+	Deprecation Deprecation
+	CORS        CORS `mapstructure:"cors"`
+}
+
+type Server struct {
+	Address      string
+	DrainSeconds int `mapstructure:"drain_seconds"`
+}
+
+type GRPC struct {
+	Address string
+	// SamePort serves gRPC on Server.Address alongside HTTP.
+	SamePort bool `mapstructure:"same_port"`
+}
+
+type Context struct {
+	Timeout int
+}
+
+type Cache struct {
+	Enabled    bool
+	TTL        int
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+type Health struct {
+	Interval  int
+	TimeoutMs int `mapstructure:"timeout_ms"`
+}
+
+type GraphQL struct {
+	MaxDepth      int `mapstructure:"max_depth"`
+	MaxComplexity int `mapstructure:"max_complexity"`
+	BatchWaitMs   int `mapstructure:"batch_wait_ms"`
+}
+
+type Webhook struct {
+	Interval    int
+	Timeout     int
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+type Auth struct {
+	Required bool
+}
+
+type Idempotency struct {
+	TTL int
+	// MaxBodyBytes caps the body of a request carrying an Idempotency-Key,
+	// which is read into memory to fingerprint it.
+	MaxBodyBytes int `mapstructure:"max_body_bytes"`
+}
+
+type RateLimit struct {
+	ratelimit.Rules `mapstructure:",squash"`
+	// DailyQuota caps the todos each user of a tenant creates per UTC
+	// day, through any API; 0 turns it off.
+	DailyQuota int64 `mapstructure:"daily_quota"`
+}
+
+type CORS struct {
+	// AllowedOrigins may call the API from a browser; "*" allows any.
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+}
+
+// Deprecation holds optional RFC 3339 timestamps for the v1 API.
+type Deprecation struct {
+	V1DeprecatedAt string `mapstructure:"v1_deprecated_at"`
+	V1Sunset       string `mapstructure:"v1_sunset"`
+}
+
+// Times parses the deprecation and sunset timestamps; empty ones are zero.
+func (d Deprecation) Times() (deprecatedAt, sunset time.Time, err error) {
+	if deprecatedAt, err = parseTime("deprecation.v1_deprecated_at", d.V1DeprecatedAt); err != nil {
+		return
+	}
+	sunset, err = parseTime("deprecation.v1_sunset", d.V1Sunset)
+	return
+}
+
+func parseTime(key, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", key, err)
+	}
+	return t, nil
+}
+
+// defaults are used for keys missing from the file and the environment.
+// Every key is listed so TODO_* variables can override it.
+var defaults = map[string]interface{}{
+	"debug":                           false,
+	"server.address":                  ":8080",
+	"server.drain_seconds":            5,
+	"grpc.address":                    ":9090",
+	"grpc.same_port":                  false,
+	"context.timeout":                 2,
+	"database.host":                   "localhost",
+	"database.port":                   "5432",
+	"database.user":                   "postgres",
+	"database.pass":                   "",
+	"database.name":                   "db_exercise",
+	"database.sslmode":                "disable",
+	"database.sslrootcert":            "",
+	"database.sslcert":                "",
+	"database.sslkey":                 "",
+	"database.migrate_on_start":       true,
+	"database.isolation":              "read committed",
+	"database.tx_max_attempts":        3,
+	"database.max_open_conns":         25,
+	"database.max_idle_conns":         25,
+	"database.conn_max_lifetime":      1800,
+	"database.conn_max_idle_time":     300,
+	"database.replica_max_lag_ms":     5000,
+	"database.replica_check_interval": 5,
+	"cache.enabled":                   true,
+	"cache.ttl":                       30,
+	"cache.max_entries":               10000,
+	"health.interval":                 10,
+	"health.timeout_ms":               2000,
+	"graphql.max_depth":               5,
+	"graphql.max_complexity":          1000,
+	"graphql.batch_wait_ms":           2,
+	"webhook.interval":                5,
+	"webhook.timeout":                 10,
+	"webhook.max_attempts":            8,
+	"auth.required":                   false,
+	"idempotency.ttl":                 86400,
+	"idempotency.max_body_bytes":      1 << 20,
+	"tenancy.header":                  "X-Tenant-ID",
+	"tenancy.base_domain":             "",
+	"ratelimit.default.rate":          20,
+	"ratelimit.default.burst":         40,
+	"ratelimit.daily_quota":           0,
+	"tracing.exporter":                "none",
+	"tracing.endpoint":                "localhost:4318",
+	"tracing.insecure":                true,
+	"tracing.file":                    "traces.json",
+	"tracing.service_name":            "crud-todo",
+	"tracing.sample_ratio":            1.0,
+	"deprecation.v1_deprecated_at":    "",
+	"deprecation.v1_sunset":           "",
+	"cors.allowed_origins":            []string{},
+}
+
+// secrets are redacted when the configuration is printed.
+var secrets = []string{"database.pass"}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	required := func(key, v string) { check(v != "", "%s wajib diisi", key) }
+	positive := func(key string, v int) { check(v > 0, "%s harus lebih dari 0", key) }
+	nonNegative := func(key string, v int) { check(v >= 0, "%s tidak boleh negatif", key) }
+
+	required("server.address", c.Server.Address)
+	nonNegative("server.drain_seconds", c.Server.DrainSeconds)
+	if !c.GRPC.SamePort {
+		required("grpc.address", c.GRPC.Address)
+	}
+	positive("context.timeout", c.Context.Timeout)
+
+	required("database.host", c.Database.Host)
+	required("database.port", c.Database.Port)
+	required("database.user", c.Database.User)
+	required("database.name", c.Database.Name)
+	if _, err := c.Database.DSN(c.Database.Host, c.Database.Port); err != nil {
+		errs = append(errs, fmt.Errorf("database.sslmode: %w", err))
+	}
+	if _, err := repository.ParseIsolation(c.Database.Isolation); err != nil {
+		errs = append(errs, fmt.Errorf("database.isolation: %w", err))
+	}
+	positive("database.tx_max_attempts", c.Database.TxMaxAttempts)
+	nonNegative("database.max_open_conns", c.Database.MaxOpenConns)
+	nonNegative("database.max_idle_conns", c.Database.MaxIdleConns)
+	nonNegative("database.conn_max_lifetime", c.Database.ConnMaxLifetime)
+	nonNegative("database.conn_max_idle_time", c.Database.ConnMaxIdleTime)
+	for i, r := range c.Database.Replicas {
+		required(fmt.Sprintf("database.replicas[%d].host", i), r.Host)
+		required(fmt.Sprintf("database.replicas[%d].port", i), r.Port)
+	}
+	if len(c.Database.Replicas) > 0 {
+		positive("database.replica_check_interval", c.Database.ReplicaCheckInterval)
+		nonNegative("database.replica_max_lag_ms", c.Database.ReplicaMaxLagMs)
+	}
+
+	if c.Cache.Enabled {
+		positive("cache.ttl", c.Cache.TTL)
+		nonNegative("cache.max_entries", c.Cache.MaxEntries)
+	}
+	positive("health.interval", c.Health.Interval)
+	positive("health.timeout_ms", c.Health.TimeoutMs)
+	nonNegative("graphql.max_depth", c.GraphQL.MaxDepth)
+	nonNegative("graphql.max_complexity", c.GraphQL.MaxComplexity)
+	nonNegative("graphql.batch_wait_ms", c.GraphQL.BatchWaitMs)
+	positive("webhook.interval", c.Webhook.Interval)
+	positive("webhook.timeout", c.Webhook.Timeout)
+	positive("webhook.max_attempts", c.Webhook.MaxAttempts)
+	positive("idempotency.ttl", c.Idempotency.TTL)
+	positive("idempotency.max_body_bytes", c.Idempotency.MaxBodyBytes)
+
+	check(c.RateLimit.Default.Rate >= 0 && c.RateLimit.Default.Burst >= 0, "ratelimit.default tidak boleh negatif")
+	for route, l := range c.RateLimit.Routes {
+		check(l.Rate >= 0 && l.Burst >= 0, "ratelimit.routes[%q] tidak boleh negatif", route)
+	}
+	check(c.RateLimit.DailyQuota >= 0, "ratelimit.daily_quota tidak boleh negatif")
+
+	switch c.Tracing.Exporter {
+	case "", "none", "otlp", "stdout", "file":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter %q tidak dikenal", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio harus di antara 0 dan 1")
+	if _, _, err := c.Deprecation.Times(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoader_Load(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+		"server": {"address": ":8000"},
+		"database": {"host": "db", "pass": "dari-file"},
+		"ratelimit": {"routes": {"POST /v2/todos": {"rate": 1, "burst": 10}}}
+	}`)
+	t.Setenv("TODO_SERVER_ADDRESS", ":9000")
+	t.Setenv("TODO_CACHE_ENABLED", "false")
+	t.Setenv("TODO_DATABASE_PASS_FILE", writeFile(t, "pass", "rahasia\n"))
+
+	l := NewLoader(path)
+	cfg, err := l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Address != ":9000" {
+		t.Errorf("server.address = %q, want the environment's :9000", cfg.Server.Address)
+	}
+	if cfg.Database.Host != "db" || cfg.Database.Pass != "rahasia" {
+		t.Errorf("database = %+v, want host from the file and pass from the secret file", cfg.Database)
+	}
+	if cfg.Cache.Enabled || cfg.Health.Interval != 10 {
+		t.Errorf("cache.enabled = %v, health.interval = %d, want false and the default 10", cfg.Cache.Enabled, cfg.Health.Interval)
+	}
+	if l := cfg.RateLimit.Routes["post /v2/todos"]; l.Rate != 1 || l.Burst != 10 {
+		t.Errorf("ratelimit.routes = %+v", cfg.RateLimit.Routes)
+	}
+	if l.File() != path {
+		t.Errorf("File() = %q, want %q", l.File(), path)
+	}
+
+	db := l.Settings()["database"].(map[string]interface{})
+	if db["pass"] != redacted || db["host"] != "db" {
+		t.Errorf("Settings() database = %v, want pass redacted", db)
+	}
+}
+
+func TestLoader_Load_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "missing file",
+			path:    filepath.Join(t.TempDir(), "nope.json"),
+			wantErr: []string{"nope.json"},
+		},
+		{
+			name:    "invalid settings are all reported",
+			env:     map[string]string{"TODO_HEALTH_INTERVAL": "0", "TODO_DATABASE_SSLMODE": "prefer", "TODO_TRACING_SAMPLE_RATIO": "2", "TODO_IDEMPOTENCY_MAX_BODY_BYTES": "0"},
+			wantErr: []string{"health.interval", "database.sslmode", "tracing.sample_ratio", "idempotency.max_body_bytes"},
+		},
+		{
+			name:    "value and secret file both set",
+			env:     map[string]string{"TODO_DATABASE_PASS": "a", "TODO_DATABASE_PASS_FILE": "/dev/null"},
+			wantErr: []string{"TODO_DATABASE_PASS"},
+		},
+		{
+			name:    "unreadable secret file",
+			env:     map[string]string{"TODO_DATABASE_PASS_FILE": filepath.Join(t.TempDir(), "nope")},
+			wantErr: []string{"TODO_DATABASE_PASS_FILE"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := tt.path
+			if path == "" {
+				path = writeFile(t, "config.json", "{}")
+			}
+			_, err := NewLoader(path).Load()
+			if err == nil {
+				t.Fatal("Load() error = nil")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_Load_NoFile(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	l := NewLoader("")
+	cfg, err := l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Address != ":8080" || l.File() != "" {
+		t.Errorf("Load() without a file = %+v from %q, want the defaults", cfg.Server, l.File())
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix starts the environment variables overriding settings: key
+// database.pass is read from TODO_DATABASE_PASS, or from the file named by
+// TODO_DATABASE_PASS_FILE.
+const EnvPrefix = "TODO"
+
+// DefaultFile is read from the working directory when no file is given.
+const DefaultFile = "config.json"
+
+const redacted = "[REDACTED]"
+
+// Loader reads the configuration from one file and the environment.
+type Loader struct {
+	path string
+
+	mu sync.Mutex
+	// v, file and settings are from the last successful Load. Settings
+	// are copied out since Watch has viper read the file into v again.
+	v        *viper.Viper
+	file     string
+	settings map[string]interface{}
+}
+
+// NewLoader returns a Loader reading path. An empty path reads DefaultFile
+// when it exists and otherwise runs on defaults and the environment.
+func NewLoader(path string) *Loader {
+	return &Loader{path: path}
+}
+
+// Load reads and validates the configuration.
+func (l *Loader) Load() (*Config, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	v.SetConfigType("json")
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	path := l.path
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err == nil {
+			path = DefaultFile
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("membaca %s: %w", path, err)
+		}
+	}
+	if err := readSecrets(v); err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.v, l.file, l.settings = v, v.ConfigFileUsed(), v.AllSettings()
+	l.mu.Unlock()
+	return &cfg, nil
+}
+
+// readSecrets sets each key whose TODO_<KEY>_FILE variable names a file to
+// the file's contents, without the trailing newline.
+func readSecrets(v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		env := envName(key)
+		file, ok := os.LookupEnv(env + "_FILE")
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(env); ok {
+			return fmt.Errorf("hanya satu dari %s dan %s_FILE boleh diisi", env, env)
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("%s_FILE: %w", env, err)
+		}
+		v.Set(key, strings.TrimRight(string(b), "\r\n"))
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// File returns the file the last Load read, or "" when it read none.
+func (l *Loader) File() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file
+}
+
+// Settings returns the effective settings of the last Load, keyed like
+// the file, with secrets redacted.
+func (l *Loader) Settings() map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.settings == nil {
+		return nil
+	}
+	return redact(l.settings, "")
+}
+
+// redact returns a copy of settings, whose keys start with prefix, with
+// non-empty secrets replaced.
+func redact(settings map[string]interface{}, prefix string) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for k, v := range settings {
+		switch {
+		case isSecret(prefix + k):
+			if fmt.Sprint(v) != "" {
+				v = redacted
+			}
+		default:
+			if nested, ok := v.(map[string]interface{}); ok {
+				v = redact(nested, prefix+k+".")
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadable lists the keys, and the prefixes of keys, whose changes the
+// server applies without a restart: the log level, tenants and their task
+// denylists, rate limits and quotas, and CORS origins.
+var reloadable = []string{"debug", "tenancy.tenants", "ratelimit", "cors"}
+
+const reloadDelay = 200 * time.Millisecond
+
+// Change is a setting that differs between two loads. Secret values are
+// redacted.
+type Change struct {
+	Key      string
+	Old, New interface{}
+	// Restart is set when the running server keeps using Old.
+	Restart bool
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Key, c.Old, c.New)
+}
+
+// Watch reloads the configuration each time its file is written and, when
+// a setting changed, calls apply with the new configuration. A file that
+// fails to load or validate is logged and ignored, so the previous
+// configuration stays in effect. Every change is logged, with a warning
+// for those that only take effect after a restart. Watch does nothing when
+// Load read no file.
+func (l *Loader) Watch(apply func(*Config)) {
+	l.mu.Lock()
+	v := l.v
+	l.mu.Unlock()
+	if v == nil || v.ConfigFileUsed() == "" {
+		return
+	}
+	// Writers truncate before writing and editors may write several
+	// times, so reload once the file has been quiet for a moment.
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	v.OnConfigChange(func(fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDelay, func() { l.reload(apply) })
+	})
+	v.WatchConfig()
+}
+
+// reload loads the configuration again and applies it when it changed.
+func (l *Loader) reload(apply func(*Config)) []Change {
+	l.mu.Lock()
+	old := l.settings
+	l.mu.Unlock()
+	cfg, err := l.Load()
+	if err != nil {
+		slog.Warn("config reload rejected, keeping the current config", "file", l.path, "error", err)
+		return nil
+	}
+	l.mu.Lock()
+	changes := diff(old, l.settings)
+	l.mu.Unlock()
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, c := range changes {
+		if c.Restart {
+			slog.Warn("config changed, restart to apply", "key", c.Key, "old", c.Old, "new", c.New)
+		} else {
+			slog.Info("config changed", "key", c.Key, "old", c.Old, "new", c.New)
+		}
+	}
+	apply(cfg)
+	return changes
+}
+
+// diff lists the leaf settings that differ between old and new, sorted by
+// key.
+func diff(old, new map[string]interface{}) []Change {
+	before, after := flatten(old), flatten(new)
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	var changes []Change
+	for k := range keys {
+		o, n := before[k], after[k]
+		// Numbers from the file are float64 and defaults int, so compare
+		// what they print as.
+		if fmt.Sprint(o) == fmt.Sprint(n) {
+			continue
+		}
+		if isSecret(k) {
+			o, n = redacted, redacted
+		}
+		changes = append(changes, Change{Key: k, Old: o, New: n, Restart: !isReloadable(k)})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// flatten turns nested settings into dotted keys; lists stay whole.
+func flatten(settings map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+				walk(prefix+k+".", nested)
+				continue
+			}
+			out[prefix+k] = v
+		}
+	}
+	walk("", settings)
+	return out
+}
+
+func isReloadable(key string) bool {
+	for _, p := range reloadable {
+		if key == p || strings.HasPrefix(key, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecret(key string) bool {
+	for _, s := range secrets {
+		if key == s {
+			return true
+		}
+	}
+	return false
+}
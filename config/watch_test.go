@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoader_Reload(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+		"debug": false,
+		"database": {"host": "db", "pass": "lama"},
+		"ratelimit": {"default": {"rate": 20, "burst": 40}}
+	}`)
+	l := NewLoader(path)
+	if _, err := l.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var applied []*Config
+	apply := func(cfg *Config) { applied = append(applied, cfg) }
+	rewrite := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rewrite(`{
+		"debug": true,
+		"database": {"host": "db2", "pass": "baru"},
+		"ratelimit": {"default": {"rate": 20, "burst": 40}},
+		"cors": {"allowed_origins": ["https://app.example.com"]}
+	}`)
+	changes := l.reload(apply)
+	want := []Change{
+		{Key: "cors.allowed_origins", Old: []string{}, New: []interface{}{"https://app.example.com"}},
+		{Key: "database.host", Old: "db", New: "db2", Restart: true},
+		{Key: "database.pass", Old: redacted, New: redacted, Restart: true},
+		{Key: "debug", Old: false, New: true},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("reload() changes = %v, want %v", changes, want)
+	}
+	if len(applied) != 1 || !applied[0].Debug || len(applied[0].CORS.AllowedOrigins) != 1 {
+		t.Fatalf("applied = %+v, want the new config once", applied)
+	}
+
+	// Dropping a key equal to its default is no change.
+	rewrite(`{
+		"debug": true,
+		"database": {"host": "db2", "pass": "baru"},
+		"cors": {"allowed_origins": ["https://app.example.com"]}
+	}`)
+	if changes := l.reload(apply); len(changes) != 0 || len(applied) != 1 {
+		t.Errorf("reload() without changes = %v, applied %d times", changes, len(applied))
+	}
+
+	rewrite(`{"debug": false, "health": {"interval": 0}}`)
+	if changes := l.reload(apply); changes != nil || len(applied) != 1 {
+		t.Errorf("invalid reload = %v, applied %d times", changes, len(applied))
+	}
+	if got := l.Settings()["debug"]; got != true {
+		t.Errorf("debug after an invalid reload = %v, want the previous true", got)
+	}
+
+	rewrite(`{not json`)
+	if changes := l.reload(apply); changes != nil || len(applied) != 1 {
+		t.Errorf("unparsable reload = %v, applied %d times", changes, len(applied))
+	}
+}
+
+func TestLoader_Watch(t *testing.T) {
+	path := writeFile(t, "config.json", `{"debug": false}`)
+	l := NewLoader(path)
+	if _, err := l.Load(); err != nil {
+		t.Fatal(err)
+	}
+	applied := make(chan *Config, 4)
+	l.Watch(func(cfg *Config) { applied <- cfg })
+
+	if err := os.WriteFile(path, []byte(`{"debug": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cfg := <-applied:
+		if !cfg.Debug {
+			t.Errorf("applied debug = false, want true")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("change to the file was not applied")
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyHandler struct {
+	ApiKeyUsecase ApiKeyUsecaseInterface
+}
+
+func NewApiKeyHandler(r *gin.RouterGroup, us ApiKeyUsecaseInterface) {
+	handler := &ApiKeyHandler{
+		ApiKeyUsecase: us,
+	}
+	r = r.Group("", RequireScope(models.ScopeAPIKeysManage))
+	r.GET("/api-keys", handler.List)
+	r.POST("/api-keys", handler.Create)
+	r.DELETE("/api-keys/:id", handler.Revoke)
+}
+
+func (a *ApiKeyHandler) List(c *gin.Context) {
+	keys, err := a.ApiKeyUsecase.Fetch(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// Create responds with the plaintext key. It is not shown again.
+func (a *ApiKeyHandler) Create(c *gin.Context) {
+	var input models.Api_key
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	key, err := a.ApiKeyUsecase.Create(c.Request.Context(), models.Api_key{
+		Name:       input.Name,
+		User:       input.User,
+		Scopes:     input.Scopes,
+		Expires_at: input.Expires_at,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Location", fmt.Sprintf("%s/%d", c.FullPath(), key.ID))
+	c.JSON(http.StatusCreated, gin.H{"data": key})
+}
+
+func (a *ApiKeyHandler) Revoke(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := a.ApiKeyUsecase.Revoke(c.Request.Context(), id); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
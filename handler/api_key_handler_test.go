@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestApiKeyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockApiKeyUsecaseInterface(ctrl)
+	r := gin.New()
+	NewApiKeyHandler(r.Group("/v2"), mockUC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		mockFn       func()
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/v2/api-keys",
+			mockFn: func() {
+				mockUC.EXPECT().Fetch(gomock.Any()).Return([]models.Api_key{{ID: 1, Prefix: "0123abcd", Hash: "h"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"prefix":"0123abcd"`,
+		},
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/v2/api-keys",
+			body:   `{"name":"ci","scopes":["todos:read"],"prefix":"ignored"}`,
+			mockFn: func() {
+				mockUC.EXPECT().
+					Create(gomock.Any(), models.Api_key{Name: "ci", Scopes: []string{models.ScopeTodosRead}}).
+					Return(models.Api_key{ID: 4, Name: "ci", Key: "ctd_0123abcd_secret"}, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/v2/api-keys/4",
+			wantBody:     `"key":"ctd_0123abcd_secret"`,
+		},
+		{
+			name:   "invalid key",
+			method: http.MethodPost,
+			path:   "/v2/api-keys",
+			body:   `{"name":"ci","scopes":["todos:admin"]}`,
+			mockFn: func() {
+				mockUC.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.Api_key{}, errors.New("scope tidak valid"))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "revoke",
+			method: http.MethodDelete,
+			path:   "/v2/api-keys/4",
+			mockFn: func() {
+				mockUC.EXPECT().Revoke(gomock.Any(), int64(4)).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "revoke unknown key",
+			method: http.MethodDelete,
+			path:   "/v2/api-keys/9",
+			mockFn: func() {
+				mockUC.EXPECT().Revoke(gomock.Any(), int64(9)).Return(models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", w.Body, tt.wantBody)
+			}
+			if strings.Contains(w.Body.String(), `"hash"`) {
+				t.Errorf("body leaks the key hash: %s", w.Body)
+			}
+		})
+	}
+}
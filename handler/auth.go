@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries an API key; "Authorization: Bearer <key>" works too.
+const APIKeyHeader = "X-API-Key"
+
+type (
+	authKey struct{}
+	userKey struct{}
+)
+
+// authState is what Authenticate learned about the caller. key is nil for
+// anonymous requests, which are only let through when required is false.
+type authState struct {
+	key      *models.Api_key
+	required bool
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// Authenticate resolves the API key of the request, if any, for the scope
+// checks further down. A key that is presented but not valid is rejected
+// here; a missing key is only rejected by RequireScope, and only when
+// required is set.
+func Authenticate(us ApiKeyUsecaseInterface, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, key, err := AuthenticateKey(c.Request.Context(), us, apiKeyFromRequest(c.Request), required)
+		if errors.Is(err, models.ErrUnauthorized) {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if key != nil {
+			c.Set(APIKeyIDKey, strconv.FormatInt(key.ID, 10))
+			if key.User != "" {
+				c.Set(UserKey, key.User)
+			}
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AuthenticateKey is Authenticate for transports other than gin: it
+// resolves raw, which is empty when no key was presented, and returns ctx
+// prepared for CheckScope and UserFromContext. An invalid key is
+// models.ErrUnauthorized.
+func AuthenticateKey(ctx context.Context, us ApiKeyUsecaseInterface, raw string, required bool) (context.Context, *models.Api_key, error) {
+	state := &authState{required: required}
+	if raw != "" {
+		key, err := us.Authenticate(ctx, raw)
+		if err != nil {
+			return ctx, nil, err
+		}
+		state.key = &key
+	}
+	ctx = context.WithValue(ctx, authKey{}, state)
+	if state.key != nil && state.key.User != "" {
+		ctx = WithUser(ctx, state.key.User)
+	}
+	return ctx, state.key, nil
+}
+
+// WithUser returns a copy of ctx acting for user.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user ctx acts for. ok is false for anonymous
+// callers and for API keys that act for a service rather than a user.
+func UserFromContext(ctx context.Context) (user string, ok bool) {
+	user, ok = ctx.Value(userKey{}).(string)
+	return user, ok && user != ""
+}
+
+// CheckScope returns models.ErrUnauthorized or models.ErrForbidden when the
+// caller of ctx may not use scope. Without Authenticate in the chain every
+// scope is granted.
+func CheckScope(ctx context.Context, scope string) error {
+	state, ok := ctx.Value(authKey{}).(*authState)
+	switch {
+	case !ok:
+		return nil
+	case state.key != nil:
+		if !state.key.HasScope(scope) {
+			return models.ErrForbidden
+		}
+		return nil
+	case state.required:
+		return models.ErrUnauthorized
+	default:
+		return nil
+	}
+}
+
+// RequireScope rejects callers whose API key does not grant scope.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := CheckScope(c.Request.Context(), scope)
+		switch {
+		case errors.Is(err, models.ErrUnauthorized):
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error(), "scope": scope})
+		default:
+			c.Next()
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestAuthenticate_RequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockKeys := NewMockApiKeyUsecaseInterface(ctrl)
+	mockTodos := NewMockTodoUsecaseInterface(ctrl)
+	reader := models.Api_key{ID: 3, Scopes: []string{models.ScopeTodosRead}}
+
+	tests := []struct {
+		name       string
+		required   bool
+		method     string
+		path       string
+		header     string
+		value      string
+		mockFn     func()
+		wantStatus int
+		wantKeyID  string
+	}{
+		{
+			name:   "key with scope",
+			method: http.MethodGet,
+			path:   "/v1/Todo/",
+			header: APIKeyHeader,
+			value:  "ctd_0123abcd_secret",
+			mockFn: func() {
+				mockKeys.EXPECT().Authenticate(gomock.Any(), "ctd_0123abcd_secret").Return(reader, nil)
+				mockTodos.EXPECT().Fetch(gomock.Any())
+			},
+			wantStatus: http.StatusOK,
+			wantKeyID:  "3",
+		},
+		{
+			name:   "bearer token",
+			method: http.MethodGet,
+			path:   "/v1/Todo/",
+			header: "Authorization",
+			value:  "Bearer ctd_0123abcd_secret",
+			mockFn: func() {
+				mockKeys.EXPECT().Authenticate(gomock.Any(), "ctd_0123abcd_secret").Return(reader, nil)
+				mockTodos.EXPECT().Fetch(gomock.Any())
+			},
+			wantStatus: http.StatusOK,
+			wantKeyID:  "3",
+		},
+		{
+			name:   "key without scope",
+			method: http.MethodDelete,
+			path:   "/v1/Todo/delete/1",
+			header: APIKeyHeader,
+			value:  "ctd_0123abcd_secret",
+			mockFn: func() {
+				mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(reader, nil)
+			},
+			wantStatus: http.StatusForbidden,
+			wantKeyID:  "3",
+		},
+		{
+			name:   "invalid key",
+			method: http.MethodGet,
+			path:   "/v1/Todo/",
+			header: APIKeyHeader,
+			value:  "ctd_0123abcd_wrong",
+			mockFn: func() {
+				mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(models.Api_key{}, models.ErrUnauthorized)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "key store unavailable",
+			method: http.MethodGet,
+			path:   "/v1/Todo/",
+			header: APIKeyHeader,
+			value:  "ctd_0123abcd_secret",
+			mockFn: func() {
+				mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(models.Api_key{}, errors.New("connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:   "anonymous when optional",
+			method: http.MethodGet,
+			path:   "/v1/Todo/",
+			mockFn: func() {
+				mockTodos.EXPECT().Fetch(gomock.Any())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "anonymous when required",
+			required:   true,
+			method:     http.MethodGet,
+			path:       "/v1/Todo/",
+			mockFn:     func() {},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			var keyID string
+			r := gin.New()
+			r.Use(Authenticate(mockKeys, tt.required), func(c *gin.Context) {
+				c.Next()
+				keyID = c.GetString(APIKeyIDKey)
+			})
+			NewTodoHandler(r.Group("/v1"), mockTodos)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if keyID != tt.wantKeyID {
+				t.Errorf("api key id = %q, want %q", keyID, tt.wantKeyID)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_User(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockKeys := NewMockApiKeyUsecaseInterface(ctrl)
+
+	for _, key := range []models.Api_key{{ID: 1, User: "siti"}, {ID: 2}} {
+		mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(key, nil)
+		var (
+			user   string
+			isUser bool
+		)
+		r := gin.New()
+		r.Use(Authenticate(mockKeys, true))
+		r.GET("/", func(c *gin.Context) {
+			user, isUser = UserFromContext(c.Request.Context())
+			if c.GetString(UserKey) != user {
+				t.Errorf("gin user = %q, want %q", c.GetString(UserKey), user)
+			}
+		})
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(APIKeyHeader, "ctd_0123abcd_secret")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if user != key.User || isUser != (key.User != "") {
+			t.Errorf("key %d acts as %q (%v), want %q", key.ID, user, isUser, key.User)
+		}
+	}
+}
+
+func TestRequireScope_WithoutAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", RequireScope(models.ScopeTodosRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
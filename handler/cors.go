@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsExposed are the response headers browsers may read besides the
+// CORS-safelisted ones.
+const corsExposed = "Location, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, " +
+	"Idempotent-Replayed, Deprecation, Sunset, Link"
+
+// CORS lets browsers on the allowed origins call the API. An origin of "*"
+// allows any. Set replaces the origins while requests are being served.
+type CORS struct {
+	origins atomic.Pointer[map[string]bool]
+}
+
+func NewCORS(origins []string) *CORS {
+	c := &CORS{}
+	c.Set(origins)
+	return c
+}
+
+// Set replaces the allowed origins, compared ignoring case.
+func (c *CORS) Set(origins []string) {
+	m := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		m[strings.ToLower(strings.TrimSuffix(o, "/"))] = true
+	}
+	c.origins.Store(&m)
+}
+
+func (c *CORS) allowed(origin string) bool {
+	origins := *c.origins.Load()
+	return origins["*"] || origins[strings.ToLower(origin)]
+}
+
+// Middleware adds CORS headers to responses for allowed origins and
+// answers their preflight requests with 204. Requests from other origins
+// get no CORS headers, so browsers keep their responses from the page.
+func (c *CORS) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+		ctx.Writer.Header().Add("Vary", "Origin")
+		if !c.allowed(origin) {
+			ctx.Next()
+			return
+		}
+		ctx.Header("Access-Control-Allow-Origin", origin)
+		if ctx.Request.Method != http.MethodOptions || ctx.GetHeader("Access-Control-Request-Method") == "" {
+			ctx.Header("Access-Control-Expose-Headers", corsExposed)
+			ctx.Next()
+			return
+		}
+		ctx.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+		if headers := ctx.GetHeader("Access-Control-Request-Headers"); headers != "" {
+			ctx.Header("Access-Control-Allow-Headers", headers)
+		}
+		ctx.Header("Access-Control-Max-Age", "600")
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
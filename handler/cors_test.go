@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cors := NewCORS([]string{"https://app.example.com/"})
+	r := gin.New()
+	r.Use(cors.Middleware())
+	r.GET("/v2/todos", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(method, origin string, header map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/v2/todos", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		header     map[string]string
+		wantStatus int
+		wantAllow  string
+	}{
+		{"same origin", http.MethodGet, "", nil, http.StatusOK, ""},
+		{"allowed origin", http.MethodGet, "https://APP.example.com", nil, http.StatusOK, "https://APP.example.com"},
+		{"other origin", http.MethodGet, "https://evil.example.com", nil, http.StatusOK, ""},
+		{
+			"preflight", http.MethodOptions, "https://app.example.com",
+			map[string]string{"Access-Control-Request-Method": "PATCH", "Access-Control-Request-Headers": "Idempotency-Key"},
+			http.StatusNoContent, "https://app.example.com",
+		},
+		{
+			"preflight from other origin", http.MethodOptions, "https://evil.example.com",
+			map[string]string{"Access-Control-Request-Method": "PATCH"},
+			http.StatusNotFound, "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.origin, tt.header)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.origin != "" && w.Header().Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", w.Header().Get("Vary"))
+			}
+		})
+	}
+
+	w := do(http.MethodOptions, "https://app.example.com", map[string]string{"Access-Control-Request-Method": "DELETE", "Access-Control-Request-Headers": "Idempotency-Key"})
+	if w.Header().Get("Access-Control-Allow-Headers") != "Idempotency-Key" {
+		t.Errorf("Access-Control-Allow-Headers = %q", w.Header().Get("Access-Control-Allow-Headers"))
+	}
+
+	cors.Set([]string{"*"})
+	if w := do(http.MethodGet, "https://evil.example.com", nil); w.Header().Get("Access-Control-Allow-Origin") == "" {
+		t.Error("origin not allowed after Set([*])")
+	}
+}
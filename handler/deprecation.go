@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes when a group of routes was deprecated, when it will
+// be removed and where clients should move to.
+type Deprecation struct {
+	At        time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// Deprecated marks every response of the group with Deprecation (RFC 9745),
+// Sunset (RFC 8594) and, when there is one, a successor-version Link.
+func Deprecated(d Deprecation) gin.HandlerFunc {
+	deprecation := "true"
+	if !d.At.IsZero() {
+		deprecation = fmt.Sprintf("@%d", d.At.Unix())
+	}
+	var sunset string
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+		if d.Successor != "" {
+			c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/v1/Todo/:id", Deprecated(Deprecation{
+		At:        time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/v2/todos",
+	}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/Todo/1", nil)
+	r.ServeHTTP(w, req)
+
+	want := map[string]string{
+		"Deprecation": "@1793491200",
+		"Sunset":      "Sat, 01 May 2027 00:00:00 GMT",
+		"Link":        `</v2/todos>; rel="successor-version"`,
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("header %s = %q, want %q", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package graphqlhandler
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+type Config struct {
+	MaxDepth      int
+	MaxComplexity int
+	// BatchWait is how long the loader collects ids before querying.
+	BatchWait time.Duration
+}
+
+type GraphQLHandler struct {
+	Schema      *graphql.Schema
+	TodoUsecase handler.TodoUsecaseInterface
+	Config      Config
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func NewGraphQLHandler(r *gin.RouterGroup, us handler.TodoUsecaseInterface, cfg Config) {
+	schema := graphql.MustParseSchema(schemaString, &Resolver{TodoUsecase: us},
+		graphql.MaxDepth(cfg.MaxDepth))
+	h := &GraphQLHandler{
+		Schema:      schema,
+		TodoUsecase: us,
+		Config:      cfg,
+	}
+	// mutations check their own write and delete scopes
+	r.POST("/graphql", handler.RequireScope(models.ScopeTodosRead), h.Query)
+}
+
+func (a *GraphQLHandler) Query(c *gin.Context) {
+	var input request
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), loaderKey, newTodoLoader(a.TodoUsecase.FetchByIDs, a.Config.BatchWait))
+	ctx = context.WithValue(ctx, costKey, &requestCost{limit: int64(a.Config.MaxComplexity)})
+	res := a.Schema.Exec(ctx, input.Query, input.OperationName, input.Variables)
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,207 @@
+package graphqlhandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func doQuery(t *testing.T, mockUC *MockTodoUsecaseInterface, cfg Config, query string) response {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewGraphQLHandler(r.Group("/v1"), mockUC, cfg)
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var res response
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+var testConfig = Config{MaxDepth: 5, MaxComplexity: 100, BatchWait: time.Millisecond}
+
+func TestGraphQLHandler_BatchesTodoLookups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+
+	mockUC.EXPECT().
+		FetchByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, ids []int64) ([]models.User_todo_list, error) {
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+				t.Errorf("FetchByIDs() ids = %v, want [1 2]", ids)
+			}
+			return []models.User_todo_list{{ID: 1, Task_name: "Belajar"}, {ID: 2, Task_name: "daily", Done: true}}, nil
+		}).
+		Times(1)
+
+	res := doQuery(t, mockUC, testConfig, `{ a: todo(id: "1") { taskName } b: todo(id: "2") { taskName done } c: todo(id: "1") { id } }`)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors = %v", res.Errors)
+	}
+	if string(res.Data["a"]) != `{"taskName":"Belajar"}` || string(res.Data["b"]) != `{"taskName":"daily","done":true}` {
+		t.Errorf("data = %v", res.Data)
+	}
+}
+
+func TestGraphQLHandler_Todos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+
+	mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{TaskNameContains: "belajar"}, 1, 0).
+		Return(models.Todo_page{Todos: []models.User_todo_list{{ID: 1, Task_name: "Belajar Go"}}, Total: 2}, nil)
+
+	res := doQuery(t, mockUC, testConfig, `{ todos(filter: {taskNameContains: "belajar"}, first: 1) { items { id } totalCount nextOffset } }`)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors = %v", res.Errors)
+	}
+	want := `{"items":[{"id":"1"}],"totalCount":2,"nextOffset":1}`
+	if string(res.Data["todos"]) != want {
+		t.Errorf("todos = %s, want %s", res.Data["todos"], want)
+	}
+
+	mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{IDs: []int64{3, 1}}, 50, 0).
+		Return(models.Todo_page{Todos: []models.User_todo_list{{ID: 1, Task_name: "Belajar Go"}, {ID: 3, Task_name: "belajar SQL"}}, Total: 2}, nil)
+	res = doQuery(t, mockUC, testConfig, `{ todos(filter: {ids: ["3", "1"]}) { items { id } totalCount nextOffset } }`)
+	want = `{"items":[{"id":"1"},{"id":"3"}],"totalCount":2,"nextOffset":null}`
+	if len(res.Errors) > 0 || string(res.Data["todos"]) != want {
+		t.Errorf("todos = %s, errors = %v, want %s", res.Data["todos"], res.Errors, want)
+	}
+}
+
+func TestGraphQLHandler_TodosBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+
+	const total = MaxFirst + 5
+	todos := make([]models.User_todo_list, MaxFirst)
+	for i := range todos {
+		todos[i] = models.User_todo_list{ID: int64(i + 2), Task_name: "Belajar"}
+	}
+	mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, MaxFirst, 1).Return(models.Todo_page{Todos: todos, Total: total}, nil)
+	mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, MaxFirst, 2147483647).Return(models.Todo_page{Total: total}, nil)
+	mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 0, math.MaxInt).Return(models.Todo_page{Total: total}, nil)
+
+	cfg := Config{MaxDepth: 5, MaxComplexity: 1000, BatchWait: time.Millisecond}
+	res := doQuery(t, mockUC, cfg, `{ todos(first: 2147483647, offset: 1) { items { id } nextOffset } }`)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors = %v", res.Errors)
+	}
+	var page struct {
+		Items      []struct{ ID string }
+		NextOffset *int
+	}
+	if err := json.Unmarshal(res.Data["todos"], &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != MaxFirst || page.Items[0].ID != "2" || page.NextOffset == nil || *page.NextOffset != MaxFirst+1 {
+		t.Errorf("todos = %s", res.Data["todos"])
+	}
+
+	res = doQuery(t, mockUC, cfg, `{ todos(first: 2147483647, offset: 2147483647) { items { id } totalCount } }`)
+	want := fmt.Sprintf(`{"items":[],"totalCount":%d}`, total)
+	if len(res.Errors) > 0 || string(res.Data["todos"]) != want {
+		t.Errorf("todos = %s, errors = %v, want %s", res.Data["todos"], res.Errors, want)
+	}
+
+	res = doQuery(t, mockUC, cfg, `{ todos(first: 0) { items { id } totalCount } }`)
+	if len(res.Errors) > 0 || string(res.Data["todos"]) != want {
+		t.Errorf("todos = %s, errors = %v, want %s", res.Data["todos"], res.Errors, want)
+	}
+}
+
+func TestGraphQLHandler_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "too deep",
+			cfg:     Config{MaxDepth: 2, MaxComplexity: 100, BatchWait: time.Millisecond},
+			query:   `{ todos { items { id } } }`,
+			wantErr: "exceeds max depth",
+		},
+		{
+			name:    "too complex",
+			cfg:     Config{MaxDepth: 5, MaxComplexity: 100, BatchWait: time.Millisecond},
+			query:   `{ todos(first: 200) { items { id taskName } } }`,
+			wantErr: errTooComplex.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := doQuery(t, mockUC, tt.cfg, tt.query)
+			if len(res.Errors) == 0 || !strings.Contains(res.Errors[0].Message, tt.wantErr) {
+				t.Errorf("errors = %v, want %q", res.Errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler_Mutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+
+	mockUC.EXPECT().Create(gomock.Any(), models.User_todo_list{Task_name: "Belajar"}).Return(models.User_todo_list{ID: 1, Task_name: "Belajar"}, nil)
+	mockUC.EXPECT().Update(gomock.Any(), models.User_todo_list{Task_name: "daily"}, int64(4)).
+		Return(models.User_todo_list{ID: 4, Task_name: "daily"}, nil)
+	mockUC.EXPECT().Delete(gomock.Any(), int64(5)).Return(nil)
+	mockUC.EXPECT().Delete(gomock.Any(), int64(6)).Return(models.ErrNotFound)
+
+	res := doQuery(t, mockUC, testConfig, `mutation {
+		createTodo(taskName: "Belajar") { id taskName }
+		updateTodo(id: "4", taskName: "daily") { id taskName }
+		deleteTodo(id: "5")
+	}`)
+	if string(res.Data["createTodo"]) != `{"id":"1","taskName":"Belajar"}` {
+		t.Errorf("createTodo = %s", res.Data["createTodo"])
+	}
+	if string(res.Data["updateTodo"]) != `{"id":"4","taskName":"daily"}` {
+		t.Errorf("updateTodo = %s", res.Data["updateTodo"])
+	}
+	if string(res.Data["deleteTodo"]) != "true" || len(res.Errors) > 0 {
+		t.Errorf("deleteTodo = %s, errors = %v", res.Data["deleteTodo"], res.Errors)
+	}
+
+	res = doQuery(t, mockUC, testConfig, `mutation { deleteTodo(id: "6") }`)
+	if len(res.Errors) != 1 || res.Errors[0].Message != models.ErrNotFound.Error() {
+		t.Errorf("errors = %v", res.Errors)
+	}
+}
@@ -0,0 +1,129 @@
+package graphqlhandler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// todoLoader batches GetByID lookups made while resolving a single request
+// into one FetchByIDs call, so aliased `todo(id:)` fields or id filters do
+// not turn into one query per todo.
+type todoLoader struct {
+	fetch func(ctx context.Context, ids []int64) ([]models.User_todo_list, error)
+	wait  time.Duration
+
+	mu    sync.Mutex
+	batch *todoBatch
+	cache map[int64]*todoBatch
+}
+
+type todoBatch struct {
+	ids  []int64
+	done chan struct{}
+	res  map[int64]models.User_todo_list
+	err  error
+}
+
+func newTodoLoader(fetch func(ctx context.Context, ids []int64) ([]models.User_todo_list, error), wait time.Duration) *todoLoader {
+	return &todoLoader{
+		fetch: fetch,
+		wait:  wait,
+		cache: make(map[int64]*todoBatch),
+	}
+}
+
+// Load returns the todo with id, or models.ErrNotFound.
+func (l *todoLoader) Load(ctx context.Context, id int64) (models.User_todo_list, error) {
+	return l.await(ctx, l.enqueue(ctx, id)[0], id)
+}
+
+// LoadMany returns the todos that exist among ids, in the order given.
+func (l *todoLoader) LoadMany(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	batches := l.enqueue(ctx, ids...)
+	todos := make([]models.User_todo_list, 0, len(ids))
+	for i, id := range ids {
+		todo, err := l.await(ctx, batches[i], id)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	return todos, nil
+}
+
+func (l *todoLoader) enqueue(ctx context.Context, ids ...int64) []*todoBatch {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	batches := make([]*todoBatch, len(ids))
+	for i, id := range ids {
+		b, ok := l.cache[id]
+		if !ok {
+			if l.batch == nil {
+				l.batch = &todoBatch{done: make(chan struct{})}
+				batch := l.batch
+				time.AfterFunc(l.wait, func() { l.dispatch(ctx, batch) })
+			}
+			b = l.batch
+			b.ids = append(b.ids, id)
+			l.cache[id] = b
+		}
+		batches[i] = b
+	}
+	return batches
+}
+
+func (l *todoLoader) await(ctx context.Context, b *todoBatch, id int64) (models.User_todo_list, error) {
+	select {
+	case <-ctx.Done():
+		return models.User_todo_list{}, ctx.Err()
+	case <-b.done:
+	}
+	if b.err != nil {
+		return models.User_todo_list{}, b.err
+	}
+	todo, ok := b.res[id]
+	if !ok {
+		return models.User_todo_list{}, models.ErrNotFound
+	}
+	return todo, nil
+}
+
+// Prime stores an already loaded todo so later loads skip the database.
+func (l *todoLoader) Prime(todo models.User_todo_list) {
+	b := &todoBatch{done: make(chan struct{}), res: map[int64]models.User_todo_list{todo.ID: todo}}
+	close(b.done)
+	l.mu.Lock()
+	l.cache[todo.ID] = b
+	l.mu.Unlock()
+}
+
+// Forget drops id from the cache after it has been mutated.
+func (l *todoLoader) Forget(id int64) {
+	l.mu.Lock()
+	delete(l.cache, id)
+	l.mu.Unlock()
+}
+
+func (l *todoLoader) dispatch(ctx context.Context, b *todoBatch) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	todos, err := l.fetch(ctx, b.ids)
+	b.err = err
+	b.res = make(map[int64]models.User_todo_list, len(todos))
+	for _, todo := range todos {
+		b.res[todo.ID] = todo
+	}
+	close(b.done)
+}
@@ -0,0 +1,225 @@
+package graphqlhandler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+var errTooComplex = errors.New("query terlalu kompleks")
+
+// MaxFirst is the largest page todos returns; a larger first is capped to it.
+const MaxFirst = 100
+
+type ctxKey int
+
+const (
+	loaderKey ctxKey = iota
+	costKey
+)
+
+// requestCost tracks the complexity budget of a single GraphQL request.
+// Each root field costs one point and the todos list additionally costs one
+// point per requested item and selected item field.
+type requestCost struct {
+	used  int64
+	limit int64
+}
+
+func charge(ctx context.Context, points int) error {
+	c, ok := ctx.Value(costKey).(*requestCost)
+	if !ok || c.limit <= 0 {
+		return nil
+	}
+	if atomic.AddInt64(&c.used, int64(points)) > c.limit {
+		return errTooComplex
+	}
+	return nil
+}
+
+func loaderFrom(ctx context.Context) *todoLoader {
+	return ctx.Value(loaderKey).(*todoLoader)
+}
+
+type Resolver struct {
+	TodoUsecase handler.TodoUsecaseInterface
+}
+
+type todoResolver struct {
+	todo models.User_todo_list
+}
+
+func (r *todoResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.todo.ID, 10))
+}
+
+func (r *todoResolver) TaskName() string {
+	return r.todo.Task_name
+}
+
+func (r *todoResolver) Done() bool {
+	return r.todo.Done
+}
+
+type todoPageResolver struct {
+	items      []*todoResolver
+	totalCount int32
+	nextOffset *int32
+}
+
+func (r *todoPageResolver) Items() []*todoResolver { return r.items }
+func (r *todoPageResolver) TotalCount() int32      { return r.totalCount }
+func (r *todoPageResolver) NextOffset() *int32     { return r.nextOffset }
+
+func parseID(id graphql.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, errors.New("id tidak valid")
+	}
+	return n, nil
+}
+
+type todosArgs struct {
+	Filter *struct {
+		Ids              *[]graphql.ID
+		TaskNameContains *string
+	}
+	First  int32
+	Offset int32
+}
+
+func (r *Resolver) Todos(ctx context.Context, args todosArgs) (*todoPageResolver, error) {
+	if args.First < 0 || args.Offset < 0 {
+		return nil, errors.New("first dan offset tidak boleh negatif")
+	}
+	first, offset := int(args.First), int(args.Offset)
+	if first > MaxFirst {
+		first = MaxFirst
+	}
+	perItem := 0
+	for _, name := range graphql.SelectedFieldNames(ctx) {
+		if strings.HasPrefix(name, "items.") {
+			perItem++
+		}
+	}
+	if err := charge(ctx, 1+first*perItem); err != nil {
+		return nil, err
+	}
+
+	var filter models.Todo_filter
+	if args.Filter != nil {
+		if args.Filter.Ids != nil {
+			filter.IDs = make([]int64, 0, len(*args.Filter.Ids))
+			for _, gid := range *args.Filter.Ids {
+				id, err := parseID(gid)
+				if err != nil {
+					return nil, err
+				}
+				filter.IDs = append(filter.IDs, id)
+			}
+		}
+		if args.Filter.TaskNameContains != nil {
+			filter.TaskNameContains = *args.Filter.TaskNameContains
+		}
+	}
+	// FetchPage reads every todo for a limit of 0; first: 0 only wants the
+	// count, which an offset past the end returns alone.
+	from := offset
+	if first == 0 {
+		from = math.MaxInt
+	}
+	res, err := r.TodoUsecase.FetchPage(ctx, filter, first, from)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &todoPageResolver{totalCount: int32(res.Total), items: make([]*todoResolver, 0, len(res.Todos))}
+	if end := offset + first; end < res.Total {
+		next := int32(end)
+		page.nextOffset = &next
+	}
+	for _, todo := range res.Todos {
+		page.items = append(page.items, &todoResolver{todo})
+	}
+	return page, nil
+}
+
+func (r *Resolver) Todo(ctx context.Context, args struct{ ID graphql.ID }) (*todoResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := loaderFrom(ctx).Load(ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{todo}, nil
+}
+
+func (r *Resolver) CreateTodo(ctx context.Context, args struct{ TaskName string }) (*todoResolver, error) {
+	if err := handler.CheckScope(ctx, models.ScopeTodosWrite); err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	todo, err := r.TodoUsecase.Create(ctx, models.User_todo_list{Task_name: args.TaskName})
+	if err != nil {
+		return nil, err
+	}
+	loaderFrom(ctx).Prime(todo)
+	return &todoResolver{todo}, nil
+}
+
+func (r *Resolver) UpdateTodo(ctx context.Context, args struct {
+	ID       graphql.ID
+	TaskName string
+}) (*todoResolver, error) {
+	if err := handler.CheckScope(ctx, models.ScopeTodosWrite); err != nil {
+		return nil, err
+	}
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := r.TodoUsecase.Update(ctx, models.User_todo_list{Task_name: args.TaskName}, id)
+	if err != nil {
+		return nil, err
+	}
+	loaderFrom(ctx).Prime(todo)
+	return &todoResolver{todo}, nil
+}
+
+func (r *Resolver) DeleteTodo(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := handler.CheckScope(ctx, models.ScopeTodosDelete); err != nil {
+		return false, err
+	}
+	if err := charge(ctx, 1); err != nil {
+		return false, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.TodoUsecase.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	loaderFrom(ctx).Forget(id)
+	return true, nil
+}
@@ -0,0 +1,458 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler/usecase_interface.go
+
+// Package graphqlhandler is a generated GoMock package.
+package graphqlhandler
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTodoUsecaseInterface is a mock of TodoUsecaseInterface interface.
+type MockTodoUsecaseInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoUsecaseInterfaceMockRecorder
+}
+
+// MockTodoUsecaseInterfaceMockRecorder is the mock recorder for MockTodoUsecaseInterface.
+type MockTodoUsecaseInterfaceMockRecorder struct {
+	mock *MockTodoUsecaseInterface
+}
+
+// NewMockTodoUsecaseInterface creates a new mock instance.
+func NewMockTodoUsecaseInterface(ctrl *gomock.Controller) *MockTodoUsecaseInterface {
+	mock := &MockTodoUsecaseInterface{ctrl: ctrl}
+	mock.recorder = &MockTodoUsecaseInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoUsecaseInterface) EXPECT() *MockTodoUsecaseInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTodoUsecaseInterface) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, todo)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Create(ctx, todo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Create), ctx, todo)
+}
+
+// Delete mocks base method.
+func (m *MockTodoUsecaseInterface) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Delete), ctx, id)
+}
+
+// Fetch mocks base method.
+func (m *MockTodoUsecaseInterface) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].([]models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Fetch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Fetch), ctx)
+}
+
+// FetchByIDs mocks base method.
+func (m *MockTodoUsecaseInterface) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByIDs indicates an expected call of FetchByIDs.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) FetchByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByIDs", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).FetchByIDs), ctx, ids)
+}
+
+// FetchPage mocks base method.
+func (m *MockTodoUsecaseInterface) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPage", ctx, filter, limit, offset)
+	ret0, _ := ret[0].(models.Todo_page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPage indicates an expected call of FetchPage.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) FetchPage(ctx, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPage", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).FetchPage), ctx, filter, limit, offset)
+}
+
+// GetByID mocks base method.
+func (m *MockTodoUsecaseInterface) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).GetByID), ctx, id)
+}
+
+// Merge mocks base method.
+func (m *MockTodoUsecaseInterface) Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, id, apply)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Merge(ctx, id, apply interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Merge), ctx, id, apply)
+}
+
+// Patch mocks base method.
+func (m *MockTodoUsecaseInterface) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, todo, fields)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Patch(ctx, id, todo, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Patch), ctx, id, todo, fields)
+}
+
+// Update mocks base method.
+func (m *MockTodoUsecaseInterface) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, todo, id)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Update(ctx, todo, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Update), ctx, todo, id)
+}
+
+// Watch mocks base method.
+func (m *MockTodoUsecaseInterface) Watch(ctx context.Context) <-chan models.Todo_event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx)
+	ret0, _ := ret[0].(<-chan models.Todo_event)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockTodoUsecaseInterfaceMockRecorder) Watch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockTodoUsecaseInterface)(nil).Watch), ctx)
+}
+
+// MockWebhookUsecaseInterface is a mock of WebhookUsecaseInterface interface.
+type MockWebhookUsecaseInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUsecaseInterfaceMockRecorder
+}
+
+// MockWebhookUsecaseInterfaceMockRecorder is the mock recorder for MockWebhookUsecaseInterface.
+type MockWebhookUsecaseInterfaceMockRecorder struct {
+	mock *MockWebhookUsecaseInterface
+}
+
+// NewMockWebhookUsecaseInterface creates a new mock instance.
+func NewMockWebhookUsecaseInterface(ctrl *gomock.Controller) *MockWebhookUsecaseInterface {
+	mock := &MockWebhookUsecaseInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookUsecaseInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUsecaseInterface) EXPECT() *MockWebhookUsecaseInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookUsecaseInterface) Create(ctx context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, sub)
+	ret0, _ := ret[0].(models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) Create(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).Create), ctx, sub)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookUsecaseInterface) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).Delete), ctx, id)
+}
+
+// Fetch mocks base method.
+func (m *MockWebhookUsecaseInterface) Fetch(ctx context.Context) ([]models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].([]models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) Fetch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).Fetch), ctx)
+}
+
+// FetchDeadDeliveries mocks base method.
+func (m *MockWebhookUsecaseInterface) FetchDeadDeliveries(ctx context.Context) ([]models.Webhook_delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchDeadDeliveries", ctx)
+	ret0, _ := ret[0].([]models.Webhook_delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchDeadDeliveries indicates an expected call of FetchDeadDeliveries.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) FetchDeadDeliveries(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchDeadDeliveries", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).FetchDeadDeliveries), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhookUsecaseInterface) GetByID(ctx context.Context, id int64) (models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).GetByID), ctx, id)
+}
+
+// RetryDelivery mocks base method.
+func (m *MockWebhookUsecaseInterface) RetryDelivery(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDelivery", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDelivery indicates an expected call of RetryDelivery.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) RetryDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDelivery", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).RetryDelivery), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockWebhookUsecaseInterface) Update(ctx context.Context, sub models.Webhook_subscription, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, sub, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookUsecaseInterfaceMockRecorder) Update(ctx, sub, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookUsecaseInterface)(nil).Update), ctx, sub, id)
+}
+
+// MockApiKeyUsecaseInterface is a mock of ApiKeyUsecaseInterface interface.
+type MockApiKeyUsecaseInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyUsecaseInterfaceMockRecorder
+}
+
+// MockApiKeyUsecaseInterfaceMockRecorder is the mock recorder for MockApiKeyUsecaseInterface.
+type MockApiKeyUsecaseInterfaceMockRecorder struct {
+	mock *MockApiKeyUsecaseInterface
+}
+
+// NewMockApiKeyUsecaseInterface creates a new mock instance.
+func NewMockApiKeyUsecaseInterface(ctrl *gomock.Controller) *MockApiKeyUsecaseInterface {
+	mock := &MockApiKeyUsecaseInterface{ctrl: ctrl}
+	mock.recorder = &MockApiKeyUsecaseInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyUsecaseInterface) EXPECT() *MockApiKeyUsecaseInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockApiKeyUsecaseInterface) Authenticate(ctx context.Context, raw string) (models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, raw)
+	ret0, _ := ret[0].(models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockApiKeyUsecaseInterfaceMockRecorder) Authenticate(ctx, raw interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockApiKeyUsecaseInterface)(nil).Authenticate), ctx, raw)
+}
+
+// Create mocks base method.
+func (m *MockApiKeyUsecaseInterface) Create(ctx context.Context, key models.Api_key) (models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockApiKeyUsecaseInterfaceMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApiKeyUsecaseInterface)(nil).Create), ctx, key)
+}
+
+// Fetch mocks base method.
+func (m *MockApiKeyUsecaseInterface) Fetch(ctx context.Context) ([]models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].([]models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockApiKeyUsecaseInterfaceMockRecorder) Fetch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockApiKeyUsecaseInterface)(nil).Fetch), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockApiKeyUsecaseInterface) Revoke(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockApiKeyUsecaseInterfaceMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockApiKeyUsecaseInterface)(nil).Revoke), ctx, id)
+}
+
+// MockShareUsecaseInterface is a mock of ShareUsecaseInterface interface.
+type MockShareUsecaseInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareUsecaseInterfaceMockRecorder
+}
+
+// MockShareUsecaseInterfaceMockRecorder is the mock recorder for MockShareUsecaseInterface.
+type MockShareUsecaseInterfaceMockRecorder struct {
+	mock *MockShareUsecaseInterface
+}
+
+// NewMockShareUsecaseInterface creates a new mock instance.
+func NewMockShareUsecaseInterface(ctrl *gomock.Controller) *MockShareUsecaseInterface {
+	mock := &MockShareUsecaseInterface{ctrl: ctrl}
+	mock.recorder = &MockShareUsecaseInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareUsecaseInterface) EXPECT() *MockShareUsecaseInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method.
+func (m *MockShareUsecaseInterface) Fetch(ctx context.Context, todoID int64) ([]models.Todo_share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, todoID)
+	ret0, _ := ret[0].([]models.Todo_share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockShareUsecaseInterfaceMockRecorder) Fetch(ctx, todoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockShareUsecaseInterface)(nil).Fetch), ctx, todoID)
+}
+
+// Revoke mocks base method.
+func (m *MockShareUsecaseInterface) Revoke(ctx context.Context, todoID int64, user string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, todoID, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockShareUsecaseInterfaceMockRecorder) Revoke(ctx, todoID, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockShareUsecaseInterface)(nil).Revoke), ctx, todoID, user)
+}
+
+// Share mocks base method.
+func (m *MockShareUsecaseInterface) Share(ctx context.Context, todoIDs []int64, user, role string) ([]models.Todo_share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", ctx, todoIDs, user, role)
+	ret0, _ := ret[0].([]models.Todo_share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Share indicates an expected call of Share.
+func (mr *MockShareUsecaseInterfaceMockRecorder) Share(ctx, todoIDs, user, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockShareUsecaseInterface)(nil).Share), ctx, todoIDs, user, role)
+}
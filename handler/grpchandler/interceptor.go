@@ -0,0 +1,159 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/proto/todopb"
+	"github.com/KennyKur/CRUD_Todo/ratelimit"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes is the scope each RPC requires. Methods missing here are
+// refused, so a new RPC cannot be served without deciding on its scope.
+var methodScopes = map[string]string{
+	todopb.TodoService_ListTodos_FullMethodName:  models.ScopeTodosRead,
+	todopb.TodoService_GetTodo_FullMethodName:    models.ScopeTodosRead,
+	todopb.TodoService_WatchTodos_FullMethodName: models.ScopeTodosRead,
+	todopb.TodoService_CreateTodo_FullMethodName: models.ScopeTodosWrite,
+	todopb.TodoService_UpdateTodo_FullMethodName: models.ScopeTodosWrite,
+	todopb.TodoService_DeleteTodo_FullMethodName: models.ScopeTodosDelete,
+}
+
+// Guard applies to gRPC calls what the HTTP middleware chain applies to
+// requests: the API key, the tenant, the scope of the method and the rate
+// limits. Limits are looked up as "GRPC <full method>", e.g.
+// "GRPC /todo.v1.TodoService/CreateTodo".
+type Guard struct {
+	ApiKeys  handler.ApiKeyUsecaseInterface
+	Required bool
+	Tenants  *tenant.Registry
+	Tenancy  tenant.Config
+	Limits   *ratelimit.Limits
+	Store    ratelimit.Store
+}
+
+// ServerOptions installs the guard on a server.
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(g.Unary),
+		grpc.ChainStreamInterceptor(g.Stream),
+	}
+}
+
+func (g *Guard) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := g.admit(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return next(ctx, req)
+}
+
+func (g *Guard) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	ctx, err := g.admit(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return next(srv, &guardedStream{ServerStream: ss, ctx: ctx})
+}
+
+type guardedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *guardedStream) Context() context.Context { return s.ctx }
+
+// admit returns the context the method runs with, or the status refusing
+// the call.
+func (g *Guard) admit(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "method %s tidak dikenal", method)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	ctx, key, err := handler.AuthenticateKey(ctx, g.ApiKeys, apiKeyFromMetadata(md), g.Required)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	var requested string
+	if g.Tenancy.Header != "" {
+		requested = first(md, strings.ToLower(g.Tenancy.Header))
+	}
+	if requested == "" {
+		requested, _ = tenant.FromHost(first(md, ":authority"), g.Tenancy.BaseDomain)
+	}
+	ctx, t, err := handler.TenantContext(ctx, g.Tenants, requested)
+	switch {
+	case errors.Is(err, handler.ErrTenantMismatch):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := handler.CheckScope(ctx, scope); err != nil {
+		return nil, toStatus(err)
+	}
+
+	client := clientKey(ctx, key, t)
+	ctx = handler.WithClient(ctx, client)
+	if g.Limits != nil {
+		d := g.Limits.Allow(ctx, g.Store, client, "GRPC "+method)
+		if !d.Allowed {
+			secs := int(math.Ceil(d.RetryAfter.Seconds()))
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", fmt.Sprint(secs)))
+			return nil, status.Errorf(codes.ResourceExhausted, "terlalu banyak permintaan, coba lagi dalam %d detik", secs)
+		}
+	}
+	return ctx, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// apiKeyFromMetadata reads the key like the HTTP API does, from x-api-key
+// or a bearer authorization.
+func apiKeyFromMetadata(md metadata.MD) string {
+	if key := first(md, strings.ToLower(handler.APIKeyHeader)); key != "" {
+		return key
+	}
+	if auth := first(md, "authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+// clientKey identifies the caller like handler.ClientKey: the API key, else
+// the user within its tenant, else the peer address.
+func clientKey(ctx context.Context, key *models.Api_key, t models.Tenant) string {
+	if key != nil {
+		return fmt.Sprintf("key:%d", key.ID)
+	}
+	if user, ok := handler.UserFromContext(ctx); ok {
+		return "user:" + t.ID + "/" + user
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "ip:" + host
+		}
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:"
+}
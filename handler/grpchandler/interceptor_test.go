@@ -0,0 +1,113 @@
+package grpchandler
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/proto/todopb"
+	"github.com/KennyKur/CRUD_Todo/ratelimit"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGuard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	mockKeys := NewMockApiKeyUsecaseInterface(ctrl)
+
+	keys := map[string]models.Api_key{
+		"reader": {ID: 1, User: "kenny", Tenant: "acme", Scopes: []string{models.ScopeTodosRead}},
+		"writer": {ID: 2, User: "siti", Tenant: "acme", Scopes: []string{models.ScopeTodosRead, models.ScopeTodosWrite}},
+	}
+	mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, raw string) (models.Api_key, error) {
+			if key, ok := keys[raw]; ok {
+				return key, nil
+			}
+			return models.Api_key{}, models.ErrUnauthorized
+		}).AnyTimes()
+
+	guard := &Guard{
+		ApiKeys:  mockKeys,
+		Required: true,
+		Tenants:  tenant.NewRegistry(map[string]models.Tenant{"acme": {}}),
+		Tenancy:  tenant.Config{Header: "X-Tenant-ID"},
+		Limits: ratelimit.NewLimits(ratelimit.Rules{Routes: map[string]ratelimit.Limit{
+			"GRPC " + todopb.TodoService_CreateTodo_FullMethodName: {Rate: 0.001, Burst: 1},
+		}}),
+		Store: ratelimit.NewMemoryStore(),
+	}
+	client := newTestClient(t, mockUC, guard.ServerOptions()...)
+
+	with := func(kv ...string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), kv...)
+	}
+
+	mockUC.EXPECT().GetByID(gomock.Any(), int64(1)).
+		DoAndReturn(func(ctx context.Context, id int64) (models.User_todo_list, error) {
+			user, _ := handler.UserFromContext(ctx)
+			if user != "kenny" || tenant.FromContext(ctx).ID != "acme" {
+				t.Errorf("user = %q, tenant = %q, want kenny in acme", user, tenant.FromContext(ctx).ID)
+			}
+			return models.User_todo_list{ID: 1, Task_name: "Belajar"}, nil
+		})
+	if _, err := client.GetTodo(with("x-api-key", "reader"), &todopb.GetTodoRequest{Id: 1}); err != nil {
+		t.Errorf("GetTodo() error = %v", err)
+	}
+
+	mockUC.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.User_todo_list{ID: 2}, nil)
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"no key", func() error {
+			_, err := client.GetTodo(context.Background(), &todopb.GetTodoRequest{Id: 1})
+			return err
+		}, codes.Unauthenticated},
+		{"unknown key", func() error {
+			_, err := client.GetTodo(with("authorization", "Bearer nope"), &todopb.GetTodoRequest{Id: 1})
+			return err
+		}, codes.Unauthenticated},
+		{"missing scope", func() error {
+			_, err := client.DeleteTodo(with("x-api-key", "reader"), &todopb.DeleteTodoRequest{Id: 1})
+			return err
+		}, codes.PermissionDenied},
+		{"other tenant", func() error {
+			_, err := client.GetTodo(with("x-api-key", "reader", "x-tenant-id", "globex"), &todopb.GetTodoRequest{Id: 1})
+			return err
+		}, codes.PermissionDenied},
+		{"within the limit", func() error {
+			_, err := client.CreateTodo(with("x-api-key", "writer"), &todopb.CreateTodoRequest{Todo: &todopb.Todo{TaskName: "Belajar"}})
+			return err
+		}, codes.OK},
+		{"over the limit", func() error {
+			_, err := client.CreateTodo(with("x-api-key", "writer"), &todopb.CreateTodoRequest{Todo: &todopb.Todo{TaskName: "Belajar"}})
+			return err
+		}, codes.ResourceExhausted},
+		{"stream without key", func() error {
+			stream, err := client.WatchTodos(context.Background(), &todopb.WatchTodosRequest{})
+			if err != nil {
+				return err
+			}
+			if _, err = stream.Recv(); err == io.EOF {
+				return nil
+			}
+			return err
+		}, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package grpchandler
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// Multiplex serves gRPC and plain HTTP on the same listener. gRPC clients
+// connect with HTTP/2 prior knowledge (h2c), so TLS is not required.
+func Multiplex(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	}), &http2.Server{})
+}
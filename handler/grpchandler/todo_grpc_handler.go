@@ -0,0 +1,147 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/proto/todopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+type TodoServer struct {
+	todopb.UnimplementedTodoServiceServer
+	TodoUsecase handler.TodoUsecaseInterface
+}
+
+func NewTodoServer(s *grpc.Server, us handler.TodoUsecaseInterface) {
+	todopb.RegisterTodoServiceServer(s, &TodoServer{
+		TodoUsecase: us,
+	})
+}
+
+// toStatus maps domain errors onto gRPC status codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrInvalidTask), errors.Is(err, models.ErrInvalidShare):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrUnauthorized):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, models.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, models.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toProto(todo models.User_todo_list) *todopb.Todo {
+	return &todopb.Todo{Id: todo.ID, TaskName: todo.Task_name, Done: todo.Done}
+}
+
+func fromProto(todo *todopb.Todo) models.User_todo_list {
+	return models.User_todo_list{ID: todo.GetId(), Task_name: todo.GetTaskName(), Done: todo.GetDone()}
+}
+
+func (s *TodoServer) ListTodos(ctx context.Context, req *todopb.ListTodosRequest) (*todopb.ListTodosResponse, error) {
+	size := int(req.GetPageSize())
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	offset := 0
+	if req.GetPageToken() != "" {
+		n, err := strconv.Atoi(req.GetPageToken())
+		if err != nil || n < 0 {
+			return nil, status.Error(codes.InvalidArgument, "page_token tidak valid")
+		}
+		offset = n
+	}
+
+	page, err := s.TodoUsecase.FetchPage(ctx, models.Todo_filter{}, size, offset)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &todopb.ListTodosResponse{}
+	if offset+size < page.Total {
+		res.NextPageToken = strconv.Itoa(offset + size)
+	}
+	for _, todo := range page.Todos {
+		res.Todos = append(res.Todos, toProto(todo))
+	}
+	return res, nil
+}
+
+func (s *TodoServer) GetTodo(ctx context.Context, req *todopb.GetTodoRequest) (*todopb.Todo, error) {
+	todo, err := s.TodoUsecase.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(todo), nil
+}
+
+func (s *TodoServer) CreateTodo(ctx context.Context, req *todopb.CreateTodoRequest) (*todopb.Todo, error) {
+	todo, err := s.TodoUsecase.Create(ctx, fromProto(req.GetTodo()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(todo), nil
+}
+
+func (s *TodoServer) UpdateTodo(ctx context.Context, req *todopb.UpdateTodoRequest) (*todopb.Todo, error) {
+	todo, err := s.TodoUsecase.Update(ctx, fromProto(req.GetTodo()), req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(todo), nil
+}
+
+func (s *TodoServer) DeleteTodo(ctx context.Context, req *todopb.DeleteTodoRequest) (*emptypb.Empty, error) {
+	if err := s.TodoUsecase.Delete(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TodoServer) WatchTodos(req *todopb.WatchTodosRequest, stream todopb.TodoService_WatchTodosServer) error {
+	ctx := stream.Context()
+	events := s.TodoUsecase.Watch(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			err := stream.Send(&todopb.TodoEvent{
+				Event:      ev.Event,
+				OccurredAt: timestamppb.New(ev.Occurred_at),
+				Todo:       toProto(ev.Data),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/proto/todopb"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, us *MockTodoUsecaseInterface, opts ...grpc.ServerOption) todopb.TodoServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(opts...)
+	NewTodoServer(s, us)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return todopb.NewTodoServiceClient(conn)
+}
+
+func TestTodoServer_ListTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	client := newTestClient(t, mockUC)
+
+	tests := []struct {
+		name          string
+		req           *todopb.ListTodosRequest
+		limit, offset int
+		page          models.Todo_page
+		wantIDs       []int64
+		wantToken     string
+		wantCode      codes.Code
+	}{
+		{
+			name:  "first page",
+			req:   &todopb.ListTodosRequest{PageSize: 2},
+			limit: 2,
+			page: models.Todo_page{Todos: []models.User_todo_list{
+				{ID: 1, Task_name: "Belajar"},
+				{ID: 2, Task_name: "Sprint Test"},
+			}, Total: 3},
+			wantIDs:   []int64{1, 2},
+			wantToken: "2",
+			wantCode:  codes.OK,
+		},
+		{
+			name:      "last page",
+			req:       &todopb.ListTodosRequest{PageSize: 2, PageToken: "2"},
+			limit:     2,
+			offset:    2,
+			page:      models.Todo_page{Todos: []models.User_todo_list{{ID: 3, Task_name: "daily"}}, Total: 3},
+			wantIDs:   []int64{3},
+			wantToken: "",
+			wantCode:  codes.OK,
+		},
+		{
+			name:     "default page size",
+			req:      &todopb.ListTodosRequest{},
+			limit:    defaultPageSize,
+			page:     models.Todo_page{Todos: []models.User_todo_list{{ID: 1, Task_name: "Belajar"}}, Total: 1},
+			wantIDs:  []int64{1},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid token",
+			req:      &todopb.ListTodosRequest{PageToken: "abc"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantCode == codes.OK {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, tt.limit, tt.offset).Return(tt.page, nil)
+			}
+			res, err := client.ListTodos(context.Background(), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("ListTodos() code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			var ids []int64
+			for _, todo := range res.Todos {
+				ids = append(ids, todo.Id)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ListTodos() ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ListTodos() ids = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+			if res.NextPageToken != tt.wantToken {
+				t.Errorf("ListTodos() next token = %q, want %q", res.NextPageToken, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestTodoServer_WritesReturnTodo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	client := newTestClient(t, mockUC)
+
+	mockUC.EXPECT().Create(gomock.Any(), models.User_todo_list{Task_name: "Belajar"}).
+		Return(models.User_todo_list{ID: 7, Task_name: "Belajar"}, nil)
+	got, err := client.CreateTodo(context.Background(), &todopb.CreateTodoRequest{Todo: &todopb.Todo{TaskName: "Belajar"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetId() != 7 || got.GetTaskName() != "Belajar" || got.GetDone() {
+		t.Errorf("CreateTodo() = %v, want id 7", got)
+	}
+
+	mockUC.EXPECT().Update(gomock.Any(), models.User_todo_list{Task_name: "Sprint Test"}, int64(3)).
+		Return(models.User_todo_list{ID: 3, Task_name: "Sprint Test", Done: true}, nil)
+	got, err = client.UpdateTodo(context.Background(), &todopb.UpdateTodoRequest{Id: 3, Todo: &todopb.Todo{TaskName: "Sprint Test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetId() != 3 || got.GetTaskName() != "Sprint Test" || !got.GetDone() {
+		t.Errorf("UpdateTodo() = %v, want the stored done todo 3", got)
+	}
+}
+
+func TestTodoServer_ErrorCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	client := newTestClient(t, mockUC)
+
+	mockUC.EXPECT().GetByID(gomock.Any(), int64(9)).Return(models.User_todo_list{}, models.ErrNotFound)
+	_, err := client.GetTodo(context.Background(), &todopb.GetTodoRequest{Id: 9})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetTodo() code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+
+	mockUC.EXPECT().Create(gomock.Any(), models.User_todo_list{Task_name: "tidur"}).Return(models.User_todo_list{}, models.ErrInvalidTask)
+	_, err = client.CreateTodo(context.Background(), &todopb.CreateTodoRequest{Todo: &todopb.Todo{TaskName: "tidur"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateTodo() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+
+	mockUC.EXPECT().Update(gomock.Any(), gomock.Any(), int64(3)).Return(models.User_todo_list{}, models.ErrInvalidShare)
+	_, err = client.UpdateTodo(context.Background(), &todopb.UpdateTodoRequest{Id: 3, Todo: &todopb.Todo{TaskName: "tidur"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateTodo() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+
+	mockUC.EXPECT().Delete(gomock.Any(), int64(1)).Return(errors.New("connection refused"))
+	_, err = client.DeleteTodo(context.Background(), &todopb.DeleteTodoRequest{Id: 1})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("DeleteTodo() code = %v, want %v", status.Code(err), codes.Internal)
+	}
+}
+
+func TestTodoServer_WatchTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	client := newTestClient(t, mockUC)
+
+	events := make(chan models.Todo_event, 1)
+	events <- models.Todo_event{
+		Event:       models.EventTodoCreated,
+		Occurred_at: time.Unix(1650000000, 0),
+		Data:        models.User_todo_list{ID: 5, Task_name: "Belajar"},
+	}
+	mockUC.EXPECT().Watch(gomock.Any()).Return(events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchTodos(ctx, &todopb.WatchTodosRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Event != models.EventTodoCreated || ev.Todo.Id != 5 || ev.OccurredAt.AsTime().Unix() != 1650000000 {
+		t.Errorf("WatchTodos() event = %v", ev)
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gin context keys under which authentication middleware stores the caller.
+const (
+	UserKey     = "user"
+	APIKeyIDKey = "api_key_id"
+	TenantKey   = "tenant"
+)
+
+// ClientKey identifies the caller for per-client limits: the API key when
+// the request used one, else the authenticated user, else the client IP.
+// User names are only unique within a tenant, so they are qualified by it.
+func ClientKey(c *gin.Context) string {
+	if id := c.GetString(APIKeyIDKey); id != "" {
+		return "key:" + id
+	}
+	if user := c.GetString(UserKey); user != "" {
+		if t := c.GetString(TenantKey); t != "" {
+			return "user:" + t + "/" + user
+		}
+		return "user:" + user
+	}
+	return "ip:" + c.ClientIP()
+}
+
+type clientCtxKey struct{}
+
+// WithClient returns a copy of ctx whose caller is identified by key, as
+// ClientKey names it.
+func WithClient(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, clientCtxKey{}, key)
+}
+
+// ClientFromContext returns the key stored by WithClient.
+func ClientFromContext(ctx context.Context) (key string, ok bool) {
+	key, ok = ctx.Value(clientCtxKey{}).(string)
+	return key, ok && key != ""
+}
+
+// IdentifyClient stores ClientKey in the request context, for the
+// usecases. It runs after Authenticate and ResolveTenant.
+func IdentifyClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithClient(c.Request.Context(), ClientKey(c)))
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		set  map[string]string
+		want string
+	}{
+		{"api key wins", map[string]string{APIKeyIDKey: "7", UserKey: "kenny"}, "key:7"},
+		{"user", map[string]string{UserKey: "kenny"}, "user:kenny"},
+		{"user of a tenant", map[string]string{UserKey: "kenny", TenantKey: "acme"}, "user:acme/kenny"},
+		{"anonymous", nil, "ip:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+			c.Request.RemoteAddr = "10.0.0.1:5000"
+			for k, v := range tt.set {
+				c.Set(k, v)
+			}
+			if got := ClientKey(c); got != tt.want {
+				t.Errorf("ClientKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var got string
+	r.GET("/", func(c *gin.Context) { c.Set(APIKeyIDKey, "7") }, IdentifyClient(), func(c *gin.Context) {
+		got, _ = ClientFromContext(c.Request.Context())
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != "key:7" {
+		t.Errorf("ClientFromContext() = %q, want %q", got, "key:7")
+	}
+}
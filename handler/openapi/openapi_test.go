@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := Validator(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	api := r.Group("/v1", validator)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api.POST("/Todos", ok)
+	api.GET("/Todo/:id", ok)
+	api.GET("/undocumented", ok)
+	r.Group("/v2", validator).PATCH("/todos/:id", ok)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"valid body", http.MethodPost, "/v1/Todos", `{"task_name":"Belajar"}`, http.StatusOK},
+		{"missing required field", http.MethodPost, "/v1/Todos", `{}`, http.StatusBadRequest},
+		{"wrong field type", http.MethodPost, "/v1/Todos", `{"task_name":5}`, http.StatusBadRequest},
+		{"missing body", http.MethodPost, "/v1/Todos", ``, http.StatusBadRequest},
+		{"valid path param", http.MethodGet, "/v1/Todo/4", ``, http.StatusOK},
+		{"non-numeric path param", http.MethodGet, "/v1/Todo/abc", ``, http.StatusBadRequest},
+		{"undocumented path", http.MethodGet, "/v1/undocumented", ``, http.StatusOK},
+		{"v2 partial body", http.MethodPatch, "/v2/todos/4", `{}`, http.StatusOK},
+		{"v2 empty task name", http.MethodPatch, "/v2/todos/4", `{"task_name":""}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d (%s)", tt.method, tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
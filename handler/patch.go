@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var errUnsupportedPatch = errors.New("content type tidak didukung, gunakan " + MergePatchType + " atau " + JSONPatchType)
+
+// checkPatchType rejects a Content-Type applyPatch cannot apply, so handlers
+// can refuse the request before loading the todo.
+func checkPatchType(c *gin.Context) error {
+	switch c.ContentType() {
+	case JSONPatchType, MergePatchType, gin.MIMEJSON, "":
+		return nil
+	}
+	return errUnsupportedPatch
+}
+
+// patchError is an error applying the request body, reported with
+// patchStatus rather than as a usecase error.
+type patchError struct{ error }
+
+func (e patchError) Unwrap() error { return e.error }
+
+// readPatch reads the request body and returns a function applying it with
+// applyPatch, for TodoUsecaseInterface.Merge. The body is read here, once,
+// because Merge may call the function again when its transaction is retried.
+func readPatch(c *gin.Context) (func(models.User_todo_list) (models.User_todo_list, []string, error), error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, patchError{err}
+	}
+	contentType := c.ContentType()
+	return func(original models.User_todo_list) (models.User_todo_list, []string, error) {
+		todo, fields, err := applyPatch(contentType, body, original)
+		if err != nil {
+			return original, nil, patchError{err}
+		}
+		return todo, fields, nil
+	}, nil
+}
+
+// applyPatch applies body to original, as a JSON Patch (RFC 6902) or a JSON
+// Merge Patch (RFC 7396) depending on contentType, and returns the result
+// with the JSON names of the fields that changed. Plain application/json is
+// treated as a merge patch. A zero id, as v1 clients send along with the
+// rest of the todo, leaves the id alone.
+func applyPatch(contentType string, body []byte, original models.User_todo_list) (models.User_todo_list, []string, error) {
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return original, nil, err
+	}
+
+	switch contentType {
+	case JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return original, nil, err
+		}
+		doc, err = patch.Apply(doc)
+		if err != nil {
+			return original, nil, err
+		}
+	case MergePatchType, gin.MIMEJSON, "":
+		doc, err = jsonpatch.MergePatch(doc, body)
+		if err != nil {
+			return original, nil, err
+		}
+	default:
+		return original, nil, errUnsupportedPatch
+	}
+	if err := checkRequired(doc); err != nil {
+		return original, nil, err
+	}
+
+	var patched models.User_todo_list
+	if err := json.Unmarshal(doc, &patched); err != nil {
+		return original, nil, err
+	}
+	switch {
+	case patched.ID != 0 && patched.ID != original.ID:
+		return original, nil, errors.New("id tidak dapat diubah")
+	case patched.Owner != original.Owner:
+		return original, nil, errors.New("owner tidak dapat diubah")
+	case patched.Role != original.Role:
+		return original, nil, errors.New("role tidak dapat diubah")
+	}
+	patched.ID = original.ID
+	fields, err := changedFields(original, patched)
+	return patched, fields, err
+}
+
+// checkRequired rejects a patched document that nulls or removes a field the
+// todo cannot do without. Unmarshalling would otherwise turn a merge-patch
+// null into the zero value and write it.
+func checkRequired(doc []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"task_name", "done"} {
+		if v, ok := fields[k]; !ok || string(v) == "null" {
+			return fmt.Errorf("%s tidak boleh kosong", k)
+		}
+	}
+	return nil
+}
+
+// changedFields compares the JSON encodings of a and b field by field.
+func changedFields(a, b models.User_todo_list) ([]string, error) {
+	var before, after map[string]interface{}
+	for _, v := range []struct {
+		todo models.User_todo_list
+		dst  *map[string]interface{}
+	}{{a, &before}, {b, &after}} {
+		raw, err := json.Marshal(v.todo)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, v.dst); err != nil {
+			return nil, err
+		}
+	}
+	var fields []string
+	for k, v := range after {
+		if !reflect.DeepEqual(before[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// patchStatus is the status for a failed checkPatchType or applyPatch.
+func patchStatus(err error) int {
+	if errors.Is(err, errUnsupportedPatch) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
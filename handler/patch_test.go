@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestApplyPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	original := models.User_todo_list{ID: 3, Task_name: "daily"}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        models.User_todo_list
+		wantFields  []string
+		wantStatus  int
+	}{
+		{
+			name:        "merge patch changes given field",
+			contentType: MergePatchType,
+			body:        `{"task_name":"Belajar"}`,
+			want:        models.User_todo_list{ID: 3, Task_name: "Belajar"},
+			wantFields:  []string{"task_name"},
+		},
+		{
+			name:        "merge patch keeps omitted field",
+			contentType: MergePatchType,
+			body:        `{}`,
+			want:        original,
+		},
+		{
+			name:        "plain json is a merge patch",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"task_name":"daily"}`,
+			want:        original,
+		},
+		{
+			name:        "json patch replace",
+			contentType: JSONPatchType,
+			body:        `[{"op":"replace","path":"/task_name","value":"Belajar"}]`,
+			want:        models.User_todo_list{ID: 3, Task_name: "Belajar"},
+			wantFields:  []string{"task_name"},
+		},
+		{
+			name:        "json patch failed test op",
+			contentType: JSONPatchType,
+			body:        `[{"op":"test","path":"/task_name","value":"x"},{"op":"replace","path":"/task_name","value":"Belajar"}]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "zero id is ignored",
+			contentType: gin.MIMEJSON,
+			body:        `{"id":0,"task_name":"Belajar"}`,
+			want:        models.User_todo_list{ID: 3, Task_name: "Belajar"},
+			wantFields:  []string{"task_name"},
+		},
+		{
+			name:        "id cannot change",
+			contentType: MergePatchType,
+			body:        `{"id":4}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "owner cannot change",
+			contentType: JSONPatchType,
+			body:        `[{"op":"add","path":"/owner","value":"siti"}]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "wrong type",
+			contentType: MergePatchType,
+			body:        `{"task_name":5}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "merge patch null task_name",
+			contentType: MergePatchType,
+			body:        `{"task_name":null}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "merge patch null done",
+			contentType: MergePatchType,
+			body:        `{"done":null}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "json patch remove task_name",
+			contentType: JSONPatchType,
+			body:        `[{"op":"remove","path":"/task_name"}]`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        `task_name=Belajar`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodPatch, "/v2/todos/3", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			apply, err := readPatch(c)
+			if err != nil {
+				t.Fatalf("readPatch() error = %v", err)
+			}
+			got, fields, err := apply(original)
+			if tt.wantStatus != 0 {
+				if !errors.As(err, new(patchError)) || patchStatus(err) != tt.wantStatus {
+					t.Fatalf("applyPatch() error = %v, want status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyPatch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("applyPatch() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("applyPatch() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(logging.New(&buf, false)))
+	r.GET("/v2/todos/:id", func(c *gin.Context) {
+		c.Set(UserKey, "kenny")
+		logging.FromContext(c.Request.Context()).Error("db down")
+		c.Status(http.StatusInternalServerError)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		wantID func(string) bool
+	}{
+		{"propagates client id", "abc-123", func(id string) bool { return id == "abc-123" }},
+		{"generates missing id", "", func(id string) bool { return len(id) == 32 }},
+		{"replaces unusable id", "bad id\n", func(id string) bool { return len(id) == 32 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/v2/todos/1", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if !tt.wantID(id) {
+				t.Fatalf("%s = %q", RequestIDHeader, id)
+			}
+			dec := json.NewDecoder(&buf)
+			var inner, access map[string]interface{}
+			if err := dec.Decode(&inner); err != nil {
+				t.Fatal(err)
+			}
+			if err := dec.Decode(&access); err != nil {
+				t.Fatal(err)
+			}
+			if inner["request_id"] != id || inner["msg"] != "db down" {
+				t.Errorf("handler log = %v, want request_id %q", inner, id)
+			}
+			if access["request_id"] != id || access["level"] != "ERROR" || access["route"] != "/v2/todos/:id" ||
+				access["status"] != float64(500) || access["user"] != "kenny" {
+				t.Errorf("access log = %v", access)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+type ShareHandler struct {
+	ShareUsecase ShareUsecaseInterface
+}
+
+type shareInput struct {
+	Todo_ids []int64 `json:"todo_ids"`
+	User     string  `json:"user"`
+	Role     string  `json:"role"`
+}
+
+func NewShareHandler(r *gin.RouterGroup, us ShareUsecaseInterface) {
+	handler := &ShareHandler{
+		ShareUsecase: us,
+	}
+	r.GET("/todos/:id/shares", RequireScope(models.ScopeTodosRead), handler.List)
+	r.POST("/todos/:id/shares", RequireScope(models.ScopeTodosWrite), handler.Invite)
+	r.DELETE("/todos/:id/shares/:user", RequireScope(models.ScopeTodosWrite), handler.Revoke)
+	r.POST("/shares", RequireScope(models.ScopeTodosWrite), handler.InviteMany)
+}
+
+func (a *ShareHandler) List(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	shares, err := a.ShareUsecase.Fetch(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shares})
+}
+
+// Invite shares one todo with a user.
+func (a *ShareHandler) Invite(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	var input shareInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	shares, err := a.ShareUsecase.Share(c.Request.Context(), []int64{id}, input.User, input.Role)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": shares[0]})
+}
+
+// InviteMany shares a group of todos with a user at once.
+func (a *ShareHandler) InviteMany(c *gin.Context) {
+	var input shareInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	shares, err := a.ShareUsecase.Share(c.Request.Context(), input.Todo_ids, input.User, input.Role)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": shares})
+}
+
+func (a *ShareHandler) Revoke(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := a.ShareUsecase.Revoke(c.Request.Context(), id, c.Param("user")); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestShareHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockShareUsecaseInterface(ctrl)
+	r := gin.New()
+	NewShareHandler(r.Group("/v2"), mockUC)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		mockFn     func()
+		wantStatus int
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/v2/todos/1/shares",
+			mockFn: func() {
+				mockUC.EXPECT().Fetch(gomock.Any(), int64(1)).Return([]models.Todo_share{{Todo_id: 1, User: "siti"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "invite",
+			method: http.MethodPost,
+			path:   "/v2/todos/1/shares",
+			body:   `{"user":"siti","role":"editor"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Share(gomock.Any(), []int64{1}, "siti", models.RoleEditor).
+					Return([]models.Todo_share{{Todo_id: 1, User: "siti", Role: models.RoleEditor}}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "invite by non owner",
+			method: http.MethodPost,
+			path:   "/v2/todos/1/shares",
+			body:   `{"user":"siti","role":"editor"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Share(gomock.Any(), []int64{1}, "siti", models.RoleEditor).Return(nil, models.ErrForbidden)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "invite group",
+			method: http.MethodPost,
+			path:   "/v2/shares",
+			body:   `{"todo_ids":[1,2],"user":"siti","role":"viewer"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Share(gomock.Any(), []int64{1, 2}, "siti", models.RoleViewer).Return(nil, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "invalid role",
+			method: http.MethodPost,
+			path:   "/v2/shares",
+			body:   `{"todo_ids":[1],"user":"siti","role":"admin"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Share(gomock.Any(), []int64{1}, "siti", "admin").Return(nil, models.ErrInvalidShare)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "revoke",
+			method: http.MethodDelete,
+			path:   "/v2/todos/1/shares/siti",
+			mockFn: func() {
+				mockUC.EXPECT().Revoke(gomock.Any(), int64(1), "siti").Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
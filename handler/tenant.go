@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrTenantMismatch = errors.New("tenant tidak sesuai dengan API key")
+	ErrUnknownTenant  = errors.New("tenant tidak dikenal")
+)
+
+// ResolveTenant decides which tenant a request belongs to: the tenant of
+// its API key, else the one named by the header, else the subdomain under
+// cfg.BaseDomain, else the default tenant. A request naming a tenant other
+// than its key's is rejected, as is one naming an unknown tenant. It must
+// run after Authenticate.
+func ResolveTenant(reg *tenant.Registry, cfg tenant.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requested string
+		if cfg.Header != "" {
+			requested = c.GetHeader(cfg.Header)
+		}
+		if requested == "" {
+			requested, _ = tenant.FromHost(c.Request.Host, cfg.BaseDomain)
+		}
+
+		ctx, t, err := TenantContext(c.Request.Context(), reg, requested)
+		switch {
+		case errors.Is(err, ErrTenantMismatch):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(TenantKey, t.ID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// TenantContext is ResolveTenant for transports other than gin, once the
+// caller has found the requested tenant, if any. ctx must come from
+// AuthenticateKey.
+func TenantContext(ctx context.Context, reg *tenant.Registry, requested string) (context.Context, models.Tenant, error) {
+	id := requested
+	if state, ok := ctx.Value(authKey{}).(*authState); ok && state.key != nil && state.key.Tenant != "" {
+		if requested != "" && !strings.EqualFold(requested, state.key.Tenant) {
+			return ctx, models.Tenant{}, ErrTenantMismatch
+		}
+		id = state.key.Tenant
+	}
+
+	t := reg.Default()
+	if id != "" {
+		var ok bool
+		if t, ok = reg.Lookup(id); !ok {
+			return ctx, models.Tenant{}, ErrUnknownTenant
+		}
+	}
+	return tenant.NewContext(ctx, t), t, nil
+}
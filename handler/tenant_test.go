@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestResolveTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockKeys := NewMockApiKeyUsecaseInterface(ctrl)
+	reg := tenant.NewRegistry(map[string]models.Tenant{
+		"acme":   {Name: "Acme"},
+		"globex": {Name: "Globex"},
+	})
+	cfg := tenant.Config{Header: "X-Tenant-ID", BaseDomain: "todo.example.com"}
+
+	tests := []struct {
+		name       string
+		host       string
+		header     string
+		keyTenant  string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "default", host: "todo.example.com", wantStatus: http.StatusOK, wantTenant: tenant.DefaultID},
+		{name: "header", host: "todo.example.com", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "subdomain", host: "globex.todo.example.com", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "header wins over subdomain", host: "globex.todo.example.com", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "key claim", host: "todo.example.com", keyTenant: "globex", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "key claim matches header", host: "todo.example.com", header: "Globex", keyTenant: "globex", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "header contradicts key", host: "todo.example.com", header: "acme", keyTenant: "globex", wantStatus: http.StatusForbidden},
+		{name: "unknown tenant", host: "initech.todo.example.com", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			r := gin.New()
+			r.Use(Authenticate(mockKeys, false), ResolveTenant(reg, cfg))
+			r.GET("/", func(c *gin.Context) {
+				got = tenant.FromContext(c.Request.Context()).ID
+				if c.GetString(TenantKey) != got {
+					t.Errorf("gin tenant = %q, want %q", c.GetString(TenantKey), got)
+				}
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "http://"+tt.host+"/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.keyTenant != "" {
+				req.Header.Set(APIKeyHeader, "ctd_0123abcd_secret")
+				mockKeys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(models.Api_key{ID: 1, Tenant: tt.keyTenant}, nil)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if got != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
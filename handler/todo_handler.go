@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/KennyKur/CRUD_Todo/models"
 
@@ -17,22 +19,34 @@ func NewTodoHandler(r *gin.RouterGroup, us TodoUsecaseInterface) {
 	handler := &TodoHandler{
 		TodoUsecase: us,
 	}
-	r.GET("/Todo/", handler.FindTodos)
-	r.GET("/Todo/:id", handler.FindTodo)
-	r.POST("/Todos", handler.CreateTodo)
-	r.PATCH("Todo/update/:id", handler.UpdateTodo)
-	r.DELETE("Todo/delete/:id", handler.DeleteTodo)
+	r.GET("/Todo/", RequireScope(models.ScopeTodosRead), handler.FindTodos)
+	r.GET("/Todo/:id", RequireScope(models.ScopeTodosRead), handler.FindTodo)
+	r.POST("/Todos", RequireScope(models.ScopeTodosWrite), handler.CreateTodo)
+	r.PATCH("Todo/update/:id", RequireScope(models.ScopeTodosWrite), handler.UpdateTodo)
+	r.DELETE("Todo/delete/:id", RequireScope(models.ScopeTodosDelete), handler.DeleteTodo)
 }
 func (a *TodoHandler) FindTodos(c *gin.Context) {
 	todos, _ := a.TodoUsecase.Fetch(c.Request.Context())
 	c.JSON(200, gin.H{"data": todos})
 }
 
+// v1Status is errorStatus, except that failures it cannot classify answer
+// 400 as v1 always has.
+func v1Status(err error) int {
+	if status := errorStatus(err); status != http.StatusInternalServerError {
+		return status
+	}
+	return http.StatusBadRequest
+}
+
 func (a *TodoHandler) FindTodo(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	todo, err := a.TodoUsecase.GetByID(c.Request.Context(), int64(id))
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	todo, err := a.TodoUsecase.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(v1Status(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(200, gin.H{"data": todo})
@@ -44,35 +58,53 @@ func (a *TodoHandler) CreateTodo(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	err := a.TodoUsecase.Create(c.Request.Context(), input)
+	todo, err := a.TodoUsecase.Create(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		setRetryAfter(c, err)
+		c.JSON(v1Status(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(200, gin.H{"message": "data berhasil ditambahkan"})
+	c.Header("Location", fmt.Sprintf("%s/Todo/%d", strings.TrimSuffix(c.FullPath(), "/Todos"), todo.ID))
+	c.JSON(http.StatusCreated, gin.H{"message": "data berhasil ditambahkan", "data": todo})
 }
 
+// UpdateTodo applies the body as a merge or JSON patch, so fields the
+// client leaves out keep their stored value.
 func (a *TodoHandler) UpdateTodo(c *gin.Context) {
-	var input models.User_todo_list
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	id, ok := pathID(c)
+	if !ok {
 		return
 	}
-	id, _ := strconv.Atoi(c.Param("id"))
-	err := a.TodoUsecase.Update(c.Request.Context(), input, int64(id))
+	if err := checkPatchType(c); err != nil {
+		c.JSON(patchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	apply, err := readPatch(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(patchStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(200, gin.H{"message": "data berhasil diubah"})
+	todo, err := a.TodoUsecase.Merge(c.Request.Context(), id, apply)
+	if err != nil {
+		status := v1Status(err)
+		if errors.As(err, new(patchError)) {
+			status = patchStatus(err)
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "data berhasil diubah", "data": todo})
 
 }
 
 func (a *TodoHandler) DeleteTodo(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	err := a.TodoUsecase.Delete(c.Request.Context(), int64(id))
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	err := a.TodoUsecase.Delete(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(v1Status(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(200, gin.H{"message": "data berhasil dihapus"})
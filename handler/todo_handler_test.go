@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/KennyKur/CRUD_Todo/models"
@@ -58,45 +63,49 @@ func TestTodoHandler_FindTodos(t *testing.T) {
 
 func TestTodoHandler_FindTodo(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-
-	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
-	ctx.Request, _ = http.NewRequest(http.MethodGet, "/Todo/:id", nil)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockUC := NewMockTodoUsecaseInterface(ctrl)
-	type fields struct {
-		TodoUsecase TodoUsecaseInterface
-	}
-	type args struct {
-		c *gin.Context
-	}
+
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		mockFn func(args)
+		name       string
+		id         string
+		mockFn     func()
+		wantStatus int
 	}{
 		{
 			name: "success to get data",
-			fields: fields{
-				TodoUsecase: mockUC,
-			},
-			args: args{
-				c: ctx,
+			id:   "2",
+			mockFn: func() {
+				mockUC.EXPECT().GetByID(gomock.Any(), int64(2)).Return(models.User_todo_list{ID: 2, Task_name: "daily"}, nil)
 			},
-			mockFn: func(a args) {
-				mockUC.EXPECT().
-					GetByID(a.c.Request.Context(), gomock.Any())
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   "999",
+			mockFn: func() {
+				mockUC.EXPECT().GetByID(gomock.Any(), int64(999)).Return(models.User_todo_list{}, models.ErrNotFound)
 			},
+			wantStatus: http.StatusNotFound,
 		},
+		{name: "non-numeric id", id: "abc", mockFn: func() {}, wantStatus: http.StatusBadRequest},
+		{name: "zero id", id: "0", mockFn: func() {}, wantStatus: http.StatusBadRequest},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/Todo/"+tt.id, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tt.id}}
 			a := &TodoHandler{
-				TodoUsecase: tt.fields.TodoUsecase,
+				TodoUsecase: mockUC,
+			}
+			a.FindTodo(ctx)
+			if w.Code != tt.wantStatus {
+				t.Errorf("TodoHandler.FindTodo() status = %d, want %d", w.Code, tt.wantStatus)
 			}
-			a.FindTodo(tt.args.c)
 		})
 	}
 }
@@ -133,7 +142,7 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 			},
 			mockFn: func(a args) {
 				mockUC.EXPECT().
-					Create(a.c.Request.Context(), mockTodo).Return(nil).AnyTimes()
+					Create(a.c.Request.Context(), mockTodo).Return(mockTodo, nil).AnyTimes()
 			},
 		},
 	}
@@ -148,94 +157,214 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 	}
 }
 
-func TestTodoHandler_UpdateTodo(t *testing.T) {
+func TestTodoHandler_CreateTodo_ReturnsTodo(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-
-	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
-	ctx.Request, _ = http.NewRequest(http.MethodPatch, "/Todo/update/:id", nil)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	mockUC.EXPECT().Create(gomock.Any(), models.User_todo_list{Task_name: "Belajar"}).
+		Return(models.User_todo_list{ID: 7, Task_name: "Belajar"}, nil)
 
-	type fields struct {
-		TodoUsecase TodoUsecaseInterface
+	r := gin.New()
+	NewTodoHandler(r.Group("/v1"), mockUC)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/Todos", strings.NewReader(`{"task_name":"Belajar"}`))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", w.Code, w.Body)
 	}
-	type args struct {
-		c *gin.Context
+	if loc := w.Header().Get("Location"); loc != "/v1/Todo/7" {
+		t.Errorf("Location = %q, want /v1/Todo/7", loc)
 	}
+	var body struct {
+		Data models.User_todo_list `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Data.ID != 7 || body.Data.Task_name != "Belajar" {
+		t.Errorf("body = %s, want the created todo", w.Body)
+	}
+}
+
+func TestTodoHandler_UpdateTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	stored := models.User_todo_list{ID: 2, Task_name: "daily"}
+
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		mockFn func(args)
+		name        string
+		id          string
+		contentType string
+		body        string
+		mockFn      func()
+		wantStatus  int
 	}{
 		{
-			name: "sucess to update data",
-			fields: fields{
-				TodoUsecase: mockUC,
+			name:        "sucess to update data",
+			contentType: MergePatchType,
+			body:        `{"task_name":"Belajar"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(2), gomock.Any()).
+					DoAndReturn(mergeOnto(t, stored, models.User_todo_list{ID: 2, Task_name: "Belajar"}, []string{"task_name"}))
 			},
-			args: args{
-				c: ctx,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "omitted field is not blanked",
+			contentType: gin.MIMEJSON,
+			body:        `{}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(2), gomock.Any()).
+					DoAndReturn(mergeOnto(t, stored, stored, nil))
 			},
-			mockFn: func(a args) {
-				mockUC.EXPECT().
-					Update(a.c.Request.Context(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        `Belajar`,
+			mockFn:      func() {},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "v1 body with zero id",
+			contentType: gin.MIMEJSON,
+			body:        `{"id":0,"task_name":"Belajar","owner":""}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(2), gomock.Any()).
+					DoAndReturn(mergeOnto(t, stored, models.User_todo_list{ID: 2, Task_name: "Belajar"}, []string{"task_name"}))
 			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "missing todo",
+			id:          "999",
+			contentType: MergePatchType,
+			body:        `{"task_name":"Belajar"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(999), gomock.Any()).Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:        "null task_name",
+			contentType: MergePatchType,
+			body:        `{"task_name":null}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(2), gomock.Any()).
+					DoAndReturn(mergeOnto(t, stored, stored, nil))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "invalid id",
+			id:          "2a",
+			contentType: MergePatchType,
+			body:        `{"task_name":"Belajar"}`,
+			mockFn:      func() {},
+			wantStatus:  http.StatusBadRequest,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mockFn(tt.args)
+			tt.mockFn()
+			if tt.id == "" {
+				tt.id = "2"
+			}
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodPatch, "/Todo/update/"+tt.id, strings.NewReader(tt.body))
+			ctx.Request.Header.Set("Content-Type", tt.contentType)
+			ctx.Params = gin.Params{{Key: "id", Value: tt.id}}
 			a := &TodoHandler{
-				TodoUsecase: tt.fields.TodoUsecase,
+				TodoUsecase: mockUC,
+			}
+			a.UpdateTodo(ctx)
+			if w.Code != tt.wantStatus {
+				t.Errorf("TodoHandler.UpdateTodo() status = %d, want %d", w.Code, tt.wantStatus)
 			}
-			a.UpdateTodo(tt.args.c)
 		})
 	}
 }
 
 func TestTodoHandler_DeleteTodo(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-
-	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
-	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/Todo/delete/:id", nil)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockUC := NewMockTodoUsecaseInterface(ctrl)
-	type fields struct {
-		TodoUsecase TodoUsecaseInterface
-	}
-	type args struct {
-		c *gin.Context
-	}
+
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		mockFn func(args)
+		name       string
+		id         string
+		mockFn     func()
+		wantStatus int
 	}{
 		{
 			name: "success to delete data",
-			fields: fields{
-				TodoUsecase: mockUC,
+			id:   "2",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
 			},
-			args: args{
-				c: ctx,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   "999",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(999)).Return(models.ErrNotFound)
 			},
-			mockFn: func(a args) {
-				mockUC.EXPECT().Delete(ctx.Request.Context(), gomock.Any())
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "forbidden",
+			id:   "3",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(3)).Return(models.ErrForbidden)
 			},
+			wantStatus: http.StatusForbidden,
 		},
+		{
+			name: "other failure keeps 400",
+			id:   "4",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(4)).Return(errors.New("some error"))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{name: "non-numeric id", id: "abc", mockFn: func() {}, wantStatus: http.StatusBadRequest},
+		{name: "negative id", id: "-1", mockFn: func() {}, wantStatus: http.StatusBadRequest},
+		{name: "overflowing id", id: "99999999999999999999", mockFn: func() {}, wantStatus: http.StatusBadRequest},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-
-			tt.mockFn(tt.args)
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodDelete, "/Todo/delete/"+tt.id, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tt.id}}
 			a := &TodoHandler{
-				TodoUsecase: tt.fields.TodoUsecase,
+				TodoUsecase: mockUC,
+			}
+			a.DeleteTodo(ctx)
+			if w.Code != tt.wantStatus {
+				t.Errorf("TodoHandler.DeleteTodo() status = %d, want %d", w.Code, tt.wantStatus)
 			}
-			a.DeleteTodo(tt.args.c)
 		})
 	}
 }
+
+// mergeOnto returns a Merge stub that applies the handler's patch to stored,
+// as the usecase does with the row it locks, and checks the result.
+func mergeOnto(t *testing.T, stored, want models.User_todo_list, wantFields []string) func(context.Context, int64, func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	return func(_ context.Context, _ int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+		got, fields, err := apply(stored)
+		if err != nil {
+			return models.User_todo_list{}, err
+		}
+		if got != want || !reflect.DeepEqual(fields, wantFields) {
+			t.Errorf("apply() = %v, %v, want %v, %v", got, fields, want, wantFields)
+		}
+		return got, nil
+	}
+}
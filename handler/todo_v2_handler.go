@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// TodoHandlerV2 serves todos under conventional resource paths.
+type TodoHandlerV2 struct {
+	TodoUsecase TodoUsecaseInterface
+}
+
+func NewTodoHandlerV2(r *gin.RouterGroup, us TodoUsecaseInterface) {
+	handler := &TodoHandlerV2{
+		TodoUsecase: us,
+	}
+	r.GET("/todos", RequireScope(models.ScopeTodosRead), handler.List)
+	r.POST("/todos", RequireScope(models.ScopeTodosWrite), handler.Create)
+	r.GET("/todos/:id", RequireScope(models.ScopeTodosRead), handler.Get)
+	r.PUT("/todos/:id", RequireScope(models.ScopeTodosWrite), handler.Replace)
+	r.PATCH("/todos/:id", RequireScope(models.ScopeTodosWrite), handler.Patch)
+	r.DELETE("/todos/:id", RequireScope(models.ScopeTodosDelete), handler.Delete)
+}
+
+// errorStatus maps domain errors onto HTTP status codes.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidTask), errors.Is(err, models.ErrInvalidShare):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func abortWithError(c *gin.Context, err error) {
+	setRetryAfter(c, err)
+	c.AbortWithStatusJSON(errorStatus(err), gin.H{"error": err.Error()})
+}
+
+// setRetryAfter tells a client over its quota when to try again.
+func setRetryAfter(c *gin.Context, err error) {
+	var quota *models.QuotaError
+	if errors.As(err, &quota) {
+		secs := int64(math.Ceil(time.Until(quota.ResetAt).Seconds()))
+		c.Header("Retry-After", strconv.FormatInt(secs, 10))
+	}
+}
+
+func pathID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "id tidak valid"})
+		return 0, false
+	}
+	return id, true
+}
+
+// pageParams reads the limit and offset query parameters. Without a limit
+// every todo from offset on is listed.
+func pageParams(c *gin.Context) (limit, offset int, ok bool) {
+	for _, p := range []struct {
+		name string
+		min  int
+		v    *int
+	}{{"limit", 1, &limit}, {"offset", 0, &offset}} {
+		s, found := c.GetQuery(p.name)
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < p.min {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": p.name + " tidak valid"})
+			return 0, 0, false
+		}
+		*p.v = n
+	}
+	return limit, offset, true
+}
+
+// List returns the todos ordered by id, a page at a time when a limit is
+// given, with next_offset set while there are more.
+func (a *TodoHandlerV2) List(c *gin.Context) {
+	limit, offset, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	page, err := a.TodoUsecase.FetchPage(c.Request.Context(), models.Todo_filter{}, limit, offset)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	todos := page.Todos
+	if todos == nil {
+		todos = []models.User_todo_list{}
+	}
+	res := gin.H{"data": todos, "total_count": page.Total}
+	if limit > 0 && offset+limit < page.Total {
+		res["next_offset"] = offset + limit
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (a *TodoHandlerV2) Get(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	todo, err := a.TodoUsecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": todo})
+}
+
+func (a *TodoHandlerV2) Create(c *gin.Context) {
+	var input models.User_todo_list
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.ID = 0
+	todo, err := a.TodoUsecase.Create(c.Request.Context(), input)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("%s/todos/%d", groupPrefix(c), todo.ID))
+	c.JSON(http.StatusCreated, gin.H{"data": todo})
+}
+
+// Replace overwrites the task of the todo with the request body; done is
+// only changed by Patch.
+func (a *TodoHandlerV2) Replace(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	var input models.User_todo_list
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	todo, err := a.TodoUsecase.Update(c.Request.Context(), input, id)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": todo})
+}
+
+// Patch applies a merge or JSON patch, only writes the fields it changed and
+// returns the todo as stored.
+func (a *TodoHandlerV2) Patch(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := checkPatchType(c); err != nil {
+		c.AbortWithStatusJSON(patchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	apply, err := readPatch(c)
+	if err != nil {
+		c.AbortWithStatusJSON(patchStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	todo, err := a.TodoUsecase.Merge(c.Request.Context(), id, apply)
+	if err != nil {
+		if errors.As(err, new(patchError)) {
+			c.AbortWithStatusJSON(patchStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": todo})
+}
+
+func (a *TodoHandlerV2) Delete(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := a.TodoUsecase.Delete(c.Request.Context(), id); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// groupPrefix returns the path the handler group is mounted on, so the
+// Location header follows wherever the routes are registered.
+func groupPrefix(c *gin.Context) string {
+	return strings.TrimSuffix(c.FullPath(), "/todos")
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTodoHandlerV2(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	r := gin.New()
+	NewTodoHandlerV2(r.Group("/v2"), mockUC)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		mockFn       func()
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/v2/todos",
+			mockFn: func() {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 0, 0).
+					Return(models.Todo_page{Todos: []models.User_todo_list{{ID: 1, Task_name: "Belajar"}}, Total: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "list page",
+			method: http.MethodGet,
+			path:   "/v2/todos?limit=2&offset=1",
+			mockFn: func() {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 2, 1).
+					Return(models.Todo_page{Todos: []models.User_todo_list{{ID: 2}, {ID: 3}}, Total: 4}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[{"id":2,"task_name":"","done":false},{"id":3,"task_name":"","done":false}],"next_offset":3,"total_count":4}`,
+		},
+		{
+			name:   "list last page",
+			method: http.MethodGet,
+			path:   "/v2/todos?limit=2&offset=2",
+			mockFn: func() {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 2, 2).
+					Return(models.Todo_page{Todos: []models.User_todo_list{{ID: 3}}, Total: 3}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[{"id":3,"task_name":"","done":false}],"total_count":3}`,
+		},
+		{
+			name:   "list past the end",
+			method: http.MethodGet,
+			path:   "/v2/todos?offset=5",
+			mockFn: func() {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 0, 5).Return(models.Todo_page{Total: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[],"total_count":1}`,
+		},
+		{
+			name:   "list empty",
+			method: http.MethodGet,
+			path:   "/v2/todos",
+			mockFn: func() {
+				mockUC.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 0, 0).Return(models.Todo_page{}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":[],"total_count":0}`,
+		},
+		{
+			name:       "list invalid limit",
+			method:     http.MethodGet,
+			path:       "/v2/todos?limit=0",
+			mockFn:     func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/v2/todos",
+			body:   `{"task_name":"Belajar"}`,
+			mockFn: func() {
+				mockUC.EXPECT().
+					Create(gomock.Any(), models.User_todo_list{Task_name: "Belajar"}).
+					Return(models.User_todo_list{ID: 7, Task_name: "Belajar"}, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/v2/todos/7",
+		},
+		{
+			name:   "create invalid task",
+			method: http.MethodPost,
+			path:   "/v2/todos",
+			body:   `{"task_name":"tidur"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Create(gomock.Any(), gomock.Any()).Return(models.User_todo_list{}, models.ErrInvalidTask)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "get missing",
+			method: http.MethodGet,
+			path:   "/v2/todos/9",
+			mockFn: func() {
+				mockUC.EXPECT().GetByID(gomock.Any(), int64(9)).Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "get invalid id",
+			method:     http.MethodGet,
+			path:       "/v2/todos/abc",
+			mockFn:     func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "replace",
+			method: http.MethodPut,
+			path:   "/v2/todos/3",
+			body:   `{"task_name":"Sprint Test"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Update(gomock.Any(), models.User_todo_list{Task_name: "Sprint Test"}, int64(3)).
+					Return(models.User_todo_list{ID: 3, Task_name: "Sprint Test"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "patch keeps omitted fields",
+			method: http.MethodPatch,
+			path:   "/v2/todos/3",
+			body:   `{}`,
+			mockFn: func() {
+				daily := models.User_todo_list{ID: 3, Task_name: "daily"}
+				mockUC.EXPECT().Merge(gomock.Any(), int64(3), gomock.Any()).DoAndReturn(mergeOnto(t, daily, daily, nil))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"id":3,"task_name":"daily","done":false}}`,
+		},
+		{
+			name:   "patch missing todo",
+			method: http.MethodPatch,
+			path:   "/v2/todos/9",
+			body:   `{"task_name":"daily"}`,
+			mockFn: func() {
+				mockUC.EXPECT().Merge(gomock.Any(), int64(9), gomock.Any()).Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "patch null task_name",
+			method: http.MethodPatch,
+			path:   "/v2/todos/3",
+			body:   `{"task_name":null}`,
+			mockFn: func() {
+				daily := models.User_todo_list{ID: 3, Task_name: "daily"}
+				mockUC.EXPECT().Merge(gomock.Any(), int64(3), gomock.Any()).DoAndReturn(mergeOnto(t, daily, daily, nil))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/v2/todos/3",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "delete failure",
+			method: http.MethodDelete,
+			path:   "/v2/todos/3",
+			mockFn: func() {
+				mockUC.EXPECT().Delete(gomock.Any(), int64(3)).Return(errors.New("connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d (%s)", tt.method, tt.path, w.Code, tt.wantStatus, w.Body.String())
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("%s %s Location = %q, want %q", tt.method, tt.path, got, tt.wantLocation)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("%s %s body = %s, want %s", tt.method, tt.path, w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTodoHandlerV2_QuotaExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	mockUC.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Return(models.User_todo_list{}, &models.QuotaError{Limit: 5, ResetAt: time.Now().Add(time.Hour)})
+
+	r := gin.New()
+	NewTodoHandlerV2(r.Group("/v2"), mockUC)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v2/todos", strings.NewReader(`{"task_name":"Belajar"}`))
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "3600" {
+		t.Errorf("status = %d, Retry-After = %q, want 429 after 3600", w.Code, w.Header().Get("Retry-After"))
+	}
+}
@@ -8,8 +8,36 @@ import (
 
 type TodoUsecaseInterface interface {
 	Fetch(ctx context.Context) ([]models.User_todo_list, error)
+	FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error)
 	GetByID(ctx context.Context, id int64) (models.User_todo_list, error)
-	Create(ctx context.Context, todo models.User_todo_list) error
-	Update(ctx context.Context, todo models.User_todo_list, id int64) error
+	FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error)
+	Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error)
+	Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error)
+	Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error)
+	Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error)
 	Delete(ctx context.Context, id int64) error
+	Watch(ctx context.Context) <-chan models.Todo_event
+}
+
+type WebhookUsecaseInterface interface {
+	Fetch(ctx context.Context) ([]models.Webhook_subscription, error)
+	GetByID(ctx context.Context, id int64) (models.Webhook_subscription, error)
+	Create(ctx context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error)
+	Update(ctx context.Context, sub models.Webhook_subscription, id int64) error
+	Delete(ctx context.Context, id int64) error
+	FetchDeadDeliveries(ctx context.Context) ([]models.Webhook_delivery, error)
+	RetryDelivery(ctx context.Context, id int64) error
+}
+
+type ApiKeyUsecaseInterface interface {
+	Fetch(ctx context.Context) ([]models.Api_key, error)
+	Create(ctx context.Context, key models.Api_key) (models.Api_key, error)
+	Revoke(ctx context.Context, id int64) error
+	Authenticate(ctx context.Context, raw string) (models.Api_key, error)
+}
+
+type ShareUsecaseInterface interface {
+	Fetch(ctx context.Context, todoID int64) ([]models.Todo_share, error)
+	Share(ctx context.Context, todoIDs []int64, user string, role string) ([]models.Todo_share, error)
+	Revoke(ctx context.Context, todoID int64, user string) error
 }
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	WebhookUsecase WebhookUsecaseInterface
+}
+
+func NewWebhookHandler(r *gin.RouterGroup, us WebhookUsecaseInterface) {
+	handler := &WebhookHandler{
+		WebhookUsecase: us,
+	}
+	r = r.Group("", RequireScope(models.ScopeWebhooksManage))
+	r.GET("/Webhook/", handler.FindWebhooks)
+	r.GET("/Webhook/:id", handler.FindWebhook)
+	r.POST("/Webhooks", handler.CreateWebhook)
+	r.PATCH("Webhook/update/:id", handler.UpdateWebhook)
+	r.DELETE("Webhook/delete/:id", handler.DeleteWebhook)
+	r.GET("/WebhookDeliveries/dead", handler.FindDeadDeliveries)
+	r.POST("/WebhookDeliveries/retry/:id", handler.RetryDelivery)
+}
+
+// webhookError answers with 404 for a missing subscription or delivery and
+// with 400 otherwise.
+func webhookError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, models.ErrNotFound) {
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (a *WebhookHandler) FindWebhooks(c *gin.Context) {
+	subs, err := a.WebhookUsecase.Fetch(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": subs})
+}
+
+func (a *WebhookHandler) FindWebhook(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	sub, err := a.WebhookUsecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"data": sub})
+}
+
+func (a *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input models.Webhook_subscription
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := a.WebhookUsecase.Create(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": sub})
+}
+
+// UpdateWebhook decodes the body onto the stored subscription, so fields the
+// body leaves out keep their values.
+func (a *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	input, err := a.WebhookUsecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.WebhookUsecase.Update(c.Request.Context(), input, id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "data berhasil diubah"})
+}
+
+func (a *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := a.WebhookUsecase.Delete(c.Request.Context(), id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "data berhasil dihapus"})
+}
+
+func (a *WebhookHandler) FindDeadDeliveries(c *gin.Context) {
+	deliveries, err := a.WebhookUsecase.FetchDeadDeliveries(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"data": deliveries})
+}
+
+func (a *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	if err := a.WebhookUsecase.RetryDelivery(c.Request.Context(), id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "pengiriman dijadwalkan ulang"})
+}
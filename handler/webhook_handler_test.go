@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+)
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockWebhookUsecaseInterface(ctrl)
+
+	tests := []struct {
+		name       string
+		body       string
+		mockFn     func()
+		wantStatus int
+	}{
+		{
+			name: "success to add subscription",
+			body: `{"url":"https://example.com/hook","event_types":["todo.created"]}`,
+			mockFn: func() {
+				mockUC.EXPECT().
+					Create(gomock.Any(), models.Webhook_subscription{
+						Url:         "https://example.com/hook",
+						Event_types: []string{models.EventTodoCreated},
+					}).
+					Return(models.Webhook_subscription{ID: 1}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid body",
+			body:       `{`,
+			mockFn:     func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid subscription",
+			body: `{"url":"nope","event_types":["todo.created"]}`,
+			mockFn: func() {
+				mockUC.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(models.Webhook_subscription{}, errors.New("url tidak valid"))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/Webhooks", strings.NewReader(tt.body))
+			a := &WebhookHandler{
+				WebhookUsecase: mockUC,
+			}
+			a.CreateWebhook(ctx)
+			if w.Code != tt.wantStatus {
+				t.Errorf("WebhookHandler.CreateWebhook() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_RetryDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockWebhookUsecaseInterface(ctrl)
+	mockUC.EXPECT().RetryDelivery(gomock.Any(), int64(9)).Return(nil)
+	mockUC.EXPECT().RetryDelivery(gomock.Any(), int64(10)).Return(models.ErrNotFound)
+
+	r := gin.New()
+	NewWebhookHandler(r.Group("/v1"), mockUC)
+	for path, want := range map[string]int{
+		"/v1/WebhookDeliveries/retry/9":  http.StatusOK,
+		"/v1/WebhookDeliveries/retry/10": http.StatusNotFound,
+		"/v1/WebhookDeliveries/retry/x":  http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("WebhookHandler.RetryDelivery(%s) status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestWebhookHandler_UpdateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockWebhookUsecaseInterface(ctrl)
+
+	stored := models.Webhook_subscription{
+		ID:          3,
+		Url:         "https://example.com/hook",
+		Event_types: []string{models.EventTodoCreated},
+		Active:      true,
+	}
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		mockFn     func()
+		wantStatus int
+	}{
+		{
+			name: "omitted fields keep their values",
+			path: "/v1/Webhook/update/3",
+			body: `{"url":"https://example.com/other"}`,
+			mockFn: func() {
+				want := stored
+				want.Url = "https://example.com/other"
+				mockUC.EXPECT().GetByID(gomock.Any(), int64(3)).Return(stored, nil)
+				mockUC.EXPECT().Update(gomock.Any(), want, int64(3)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			path:       "/v1/Webhook/update/x",
+			body:       `{}`,
+			mockFn:     func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "subscription not found",
+			path: "/v1/Webhook/update/4",
+			body: `{"active":false}`,
+			mockFn: func() {
+				mockUC.EXPECT().GetByID(gomock.Any(), int64(4)).Return(models.Webhook_subscription{}, models.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFn()
+			r := gin.New()
+			NewWebhookHandler(r.Group("/v1"), mockUC)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, tt.path, strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("WebhookHandler.UpdateWebhook() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
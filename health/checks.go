@@ -0,0 +1,30 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/migrations"
+)
+
+// Database pings db.
+func Database(db *sql.DB, timeout time.Duration) Check {
+	return Check{Name: "database", Timeout: timeout, Fn: db.PingContext}
+}
+
+// Migrations fails while any embedded migration has not been applied.
+func Migrations(db *sql.DB, timeout time.Duration) Check {
+	return Check{Name: "migrations", Timeout: timeout, Fn: func(ctx context.Context) error {
+		pending, err := migrations.Pending(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("migrasi belum dijalankan: %s", strings.Join(pending, ", "))
+		}
+		return nil
+	}}
+}
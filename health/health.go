@@ -0,0 +1,151 @@
+// Package health runs dependency checks in the background and serves the
+// cached results as liveness and readiness endpoints.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check is one dependency probe. Fn should return promptly once ctx is done.
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Fn      func(ctx context.Context) error
+}
+
+// Result is the outcome of the latest run of a check.
+type Result struct {
+	Status    string    `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the readiness breakdown served by /readyz.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs its checks periodically and keeps the latest results, so
+// probes never wait on a dependency.
+type Checker struct {
+	checks   []Check
+	draining atomic.Bool
+
+	mu      sync.RWMutex
+	results map[string]Result
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks, results: map[string]Result{}}
+}
+
+// RunOnce runs every check concurrently and stores the results.
+func (h *Checker) RunOnce(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			res := run(ctx, check)
+			h.mu.Lock()
+			h.results[check.Name] = res
+			h.mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+}
+
+func run(ctx context.Context, check Check) Result {
+	if check.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, check.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := check.Fn(ctx)
+	res := Result{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Run checks immediately and then every interval until ctx is done.
+func (h *Checker) Run(ctx context.Context, interval time.Duration) {
+	h.RunOnce(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.RunOnce(ctx)
+		}
+	}
+}
+
+// SetDraining makes the instance report not ready, so it stops receiving
+// traffic while in-flight requests finish.
+func (h *Checker) SetDraining(draining bool) {
+	h.draining.Store(draining)
+}
+
+// Report returns the cached results. The instance is ready when it is not
+// draining and every check has run and passed.
+func (h *Checker) Report() Report {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	rep := Report{Status: StatusOK, Checks: make(map[string]Result, len(h.checks)+1)}
+	for _, check := range h.checks {
+		res, ok := h.results[check.Name]
+		if !ok {
+			res = Result{Status: StatusFail, Error: "belum diperiksa"}
+		}
+		if res.Status != StatusOK {
+			rep.Status = StatusFail
+		}
+		rep.Checks[check.Name] = res
+	}
+	draining := Result{Status: StatusOK}
+	if h.draining.Load() {
+		draining = Result{Status: StatusFail, Error: "instance sedang berhenti"}
+		rep.Status = StatusFail
+	}
+	rep.Checks["draining"] = draining
+	return rep
+}
+
+// NewHandler serves /healthz, which only shows the process is up, and
+// /readyz, which returns the cached report with 503 when not ready.
+func NewHandler(r *gin.Engine, h *Checker) {
+	r.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+	})
+	r.GET("/readyz", func(c *gin.Context) {
+		rep := h.Report()
+		status := http.StatusOK
+		if rep.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, rep)
+	})
+}
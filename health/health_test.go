@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbErr := error(nil)
+	h := NewChecker(
+		Check{Name: "database", Fn: func(context.Context) error { return dbErr }},
+		Check{Name: "slow", Timeout: 10 * time.Millisecond, Fn: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	)
+	r := gin.New()
+	NewHandler(r, h)
+
+	readyz := func() (int, Report) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		r.ServeHTTP(w, req)
+		var rep Report
+		if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, rep
+	}
+
+	if code, rep := readyz(); code != http.StatusServiceUnavailable || rep.Checks["database"].Status != StatusFail {
+		t.Errorf("before first run: %d %+v, want 503 with unchecked database", code, rep)
+	}
+
+	h.RunOnce(context.Background())
+	code, rep := readyz()
+	if code != http.StatusServiceUnavailable || rep.Checks["database"].Status != StatusOK || rep.Checks["slow"].Status != StatusFail {
+		t.Errorf("with a timed out check: %d %+v", code, rep)
+	}
+
+	h.checks = h.checks[:1]
+	delete(h.results, "slow")
+	code, rep = readyz()
+	if code != http.StatusOK || rep.Status != StatusOK {
+		t.Errorf("all checks passing: %d %+v, want 200", code, rep)
+	}
+
+	dbErr = errors.New("connection refused")
+	h.RunOnce(context.Background())
+	if code, rep := readyz(); code != http.StatusServiceUnavailable || rep.Checks["database"].Error != "connection refused" {
+		t.Errorf("database down: %d %+v", code, rep)
+	}
+
+	dbErr = nil
+	h.RunOnce(context.Background())
+	h.SetDraining(true)
+	if code, rep := readyz(); code != http.StatusServiceUnavailable || rep.Checks["draining"].Status != StatusFail {
+		t.Errorf("draining: %d %+v", code, rep)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("/healthz while draining = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,175 @@
+// Package idempotency lets clients retry POST and PATCH requests safely.
+// A request carrying an Idempotency-Key header runs once; retries with the
+// same key and body get the stored response back until it expires. State
+// lives behind a small store interface so several instances can share it;
+// MemoryStore keeps it in process.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses served from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// replayHeaders are the response headers stored alongside the body.
+var replayHeaders = []string{"Content-Type", "Location"}
+
+// Response is a stored response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record is what the store knows about a key. Response is nil while the
+// first request is still running.
+type Record struct {
+	Fingerprint string
+	Response    *Response
+}
+
+// Store keeps records until they expire. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Reserve stores an in-flight record for key unless one exists, and
+	// reports whether it did. Otherwise it returns the existing record.
+	Reserve(ctx context.Context, key, fingerprint string, expiresAt time.Time) (Record, bool, error)
+	// Complete stores the response of a reserved key.
+	Complete(ctx context.Context, key string, res Response) error
+	// Release forgets a reserved key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Middleware makes POST and PATCH requests with an Idempotency-Key header
+// idempotent per tenant and client, as identified by key, for ttl. A retry
+// with another method, path or body gets 409, as does one arriving while the
+// first is still running. Bodies over maxBody bytes get 413. Responses of 429
+// and 5xx are not stored so the request can be retried; store errors let the
+// request through.
+func Middleware(store Store, ttl time.Duration, maxBody int64, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idemKey := c.GetHeader(Header)
+		if ttl <= 0 || idemKey == "" || (c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPatch) {
+			c.Next()
+			return
+		}
+		if len(idemKey) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key terlalu panjang"})
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "body terlalu besar"})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "body tidak dapat dibaca"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		storeKey := tenant.FromContext(ctx).ID + "|" + key(c) + "|" + idemKey
+		fingerprint := fingerprint(c.Request, body)
+		rec, reserved, err := store.Reserve(ctx, storeKey, fingerprint, time.Now().Add(ttl))
+		if err != nil {
+			logging.FromContext(ctx).Warn("idempotency store", slog.Any("err", err))
+			c.Next()
+			return
+		}
+		if !reserved {
+			replay(c, rec, fingerprint)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		done := false
+		defer func() {
+			// Runs on panics too, which must not leave the key in flight.
+			if !done {
+				if err := store.Release(context.WithoutCancel(ctx), storeKey); err != nil {
+					logging.FromContext(ctx).Warn("idempotency store", slog.Any("err", err))
+				}
+			}
+		}()
+		c.Next()
+
+		status := w.Status()
+		if status == http.StatusTooManyRequests || status >= http.StatusInternalServerError {
+			return
+		}
+		res := Response{Status: status, Header: http.Header{}, Body: w.body.Bytes()}
+		for _, h := range replayHeaders {
+			if v := w.Header().Values(h); len(v) > 0 {
+				res.Header[h] = v
+			}
+		}
+		if err := store.Complete(context.WithoutCancel(ctx), storeKey, res); err != nil {
+			logging.FromContext(ctx).Warn("idempotency store", slog.Any("err", err))
+			return
+		}
+		done = true
+	}
+}
+
+func replay(c *gin.Context, rec Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Idempotency-Key sudah dipakai untuk permintaan lain"})
+	case rec.Response == nil:
+		c.Header("Retry-After", "1")
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "permintaan dengan Idempotency-Key ini masih diproses"})
+	default:
+		for h, v := range rec.Response.Header {
+			c.Writer.Header()[h] = v
+		}
+		c.Header(ReplayedHeader, "true")
+		c.Status(rec.Response.Status)
+		c.Writer.Write(rec.Response.Body)
+		c.Abort()
+	}
+}
+
+// fingerprint identifies a request by method, path, query and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder keeps a copy of the body written through it.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,186 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var created atomic.Int64
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Tenant"); id != "" {
+			c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), models.Tenant{ID: id}))
+		}
+	})
+	r.Use(Middleware(NewMemoryStore(), time.Hour, 1<<10, func(c *gin.Context) string { return c.GetHeader("X-Client") }))
+	r.POST("/v1/Todos", func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "gagal"})
+			return
+		}
+		id := strconv.FormatInt(created.Add(1), 10)
+		c.Header("Location", "/v2/todos/"+id)
+		c.JSON(http.StatusCreated, gin.H{"data": gin.H{"id": id}})
+	})
+
+	do := func(path, client, key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("X-Client", client)
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do("/v1/Todos", "a", "k1", `{"task_name":"Belajar"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(ReplayedHeader) != "" {
+		t.Fatalf("first request status = %d headers %v", first.Code, first.Header())
+	}
+
+	retry := do("/v1/Todos", "a", "k1", `{"task_name":"Belajar"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() ||
+		retry.Header().Get("Location") != first.Header().Get("Location") || retry.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("retry = %d %q %v, want the first response replayed", retry.Code, retry.Body, retry.Header())
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		client     string
+		key        string
+		body       string
+		wantStatus int
+	}{
+		{"different body", "/v1/Todos", "a", "k1", `{"task_name":"Tidur"}`, http.StatusConflict},
+		{"different path", "/v1/Todos?x=1", "a", "k1", `{"task_name":"Belajar"}`, http.StatusConflict},
+		{"other client", "/v1/Todos", "b", "k1", `{"task_name":"Belajar"}`, http.StatusCreated},
+		{"no key", "/v1/Todos", "a", "", `{"task_name":"Belajar"}`, http.StatusCreated},
+		{"key too long", "/v1/Todos", "a", strings.Repeat("k", 256), `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.path, tt.client, tt.key, tt.body); w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+
+	// the same client and key in another tenant is another request
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/Todos", strings.NewReader(`{"task_name":"Belajar"}`))
+	req.Header.Set("X-Client", "a")
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set(Header, "k1")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated || w.Header().Get(ReplayedHeader) != "" {
+		t.Errorf("other tenant = %d %v, want a new todo", w.Code, w.Header())
+	}
+	if n := created.Load(); n != 4 {
+		t.Errorf("handler created %d todos, want 4", n)
+	}
+
+	if w := do("/v1/Todos?fail=1", "a", "k2", `{}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("failing request status = %d", w.Code)
+	}
+	if w := do("/v1/Todos?fail=1", "a", "k2", `{}`); w.Header().Get(ReplayedHeader) != "" {
+		t.Error("a 5xx response was replayed, want the request to run again")
+	}
+}
+
+func TestMiddleware_Concurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var runs atomic.Int64
+	release := make(chan struct{})
+	started := make(chan struct{})
+	r := gin.New()
+	r.Use(Middleware(NewMemoryStore(), time.Hour, 1<<10, func(c *gin.Context) string { return "client" }))
+	r.PATCH("/v2/todos/:id", func(c *gin.Context) {
+		runs.Add(1)
+		close(started)
+		<-release
+		c.Status(http.StatusNoContent)
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPatch, "/v2/todos/1", strings.NewReader(`{"task_name":"Belajar"}`))
+		req.Header.Set(Header, "k")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	var wg sync.WaitGroup
+	var first *httptest.ResponseRecorder
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = do()
+	}()
+	<-started
+	if w := do(); w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("duplicate in flight status = %d headers %v, want 409 with Retry-After", w.Code, w.Header())
+	}
+	close(release)
+	wg.Wait()
+
+	if first.Code != http.StatusNoContent {
+		t.Errorf("first status = %d, want 204", first.Code)
+	}
+	if w := do(); w.Code != http.StatusNoContent || w.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("retry status = %d, want the replayed 204", w.Code)
+	}
+	if n := runs.Load(); n != 1 {
+		t.Errorf("handler ran %d times, want 1", n)
+	}
+}
+
+func TestMiddleware_BodyTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls atomic.Int64
+	r := gin.New()
+	r.Use(Middleware(NewMemoryStore(), time.Hour, 16, func(c *gin.Context) string { return "client" }))
+	r.POST("/v1/Todos", func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusCreated)
+	})
+
+	for _, tt := range []struct {
+		name       string
+		key        string
+		body       string
+		wantStatus int
+	}{
+		{"within the limit", "k1", `{"a":1}`, http.StatusCreated},
+		{"over the limit", "k2", `{"task_name":"Belajar"}`, http.StatusRequestEntityTooLarge},
+		{"no key is not read", "", `{"task_name":"Belajar"}`, http.StatusCreated},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/v1/Todos", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(Header, tt.key)
+			}
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("handler ran %d times, want 2", n)
+	}
+}
@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const pruneEvery = 1024
+
+type entry struct {
+	Record
+	expiresAt time.Time
+}
+
+// MemoryStore keeps records in process. Retries reaching another instance
+// run again, so use a shared Store when running several replicas.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	calls   int
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]*entry{}, now: time.Now}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, expiresAt time.Time) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.prune(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		return e.Record, false, nil
+	}
+	s.entries[key] = &entry{Record: Record{Fingerprint: fingerprint}, expiresAt: expiresAt}
+	return Record{}, true, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key string, res Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.Response = &res
+	}
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// prune drops expired records now and then; they are indistinguishable
+// from missing ones.
+func (s *MemoryStore) prune(now time.Time) {
+	s.calls++
+	if s.calls%pruneEvery != 0 {
+		return
+	}
+	for k, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+}
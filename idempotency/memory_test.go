@@ -0,0 +1,36 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Unix(1650000000, 0)
+	s.now = func() time.Time { return now }
+
+	if _, ok, _ := s.Reserve(ctx, "k", "a", now.Add(time.Hour)); !ok {
+		t.Fatal("first Reserve() did not reserve")
+	}
+	rec, ok, _ := s.Reserve(ctx, "k", "b", now.Add(time.Hour))
+	if ok || rec.Fingerprint != "a" || rec.Response != nil {
+		t.Fatalf("second Reserve() = %+v, %v, want the in-flight record", rec, ok)
+	}
+
+	s.Complete(ctx, "k", Response{Status: 201, Body: []byte("{}")})
+	if rec, _, _ := s.Reserve(ctx, "k", "a", now.Add(time.Hour)); rec.Response == nil || rec.Response.Status != 201 {
+		t.Fatalf("Reserve() after Complete() = %+v, want the response", rec)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := s.Reserve(ctx, "k", "b", now.Add(time.Hour)); !ok {
+		t.Error("Reserve() after expiry did not reserve")
+	}
+	s.Release(ctx, "k")
+	if _, ok, _ := s.Reserve(ctx, "k", "c", now.Add(time.Hour)); !ok {
+		t.Error("Reserve() after Release() did not reserve")
+	}
+}
@@ -0,0 +1,47 @@
+// Package logging provides the structured logger shared by every layer.
+// Handlers attach a request-scoped logger to the context and the usecases
+// and repositories log through FromContext, so every line carries the
+// request it belongs to.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// New returns a JSON logger that includes debug records when debug is set.
+func New(w io.Writer, debug bool) *slog.Logger {
+	return NewWithLevel(w, Level(debug))
+}
+
+// NewWithLevel returns a JSON logger dropping records below level. Pass a
+// *slog.LevelVar to change the level while the logger is in use.
+func NewWithLevel(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Level is the minimum level logged in debug mode or outside it.
+func Level(debug bool) slog.Level {
+	if debug {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != slog.Default() {
+		t.Errorf("FromContext() without logger = %v, want slog.Default()", got)
+	}
+
+	var buf bytes.Buffer
+	l := New(&buf, false).With("request_id", "abc")
+	FromContext(NewContext(context.Background(), l)).Info("hello")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log line is not JSON: %v (%s)", err, buf.String())
+	}
+	if line["request_id"] != "abc" || line["msg"] != "hello" {
+		t.Errorf("log line = %v", line)
+	}
+}
+
+func TestNewLevel(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, false).Debug("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("debug record logged without debug: %s", buf.String())
+	}
+	New(&buf, true).Debug("shown")
+	if buf.Len() == 0 {
+		t.Error("debug record dropped with debug enabled")
+	}
+}
+
+func TestNewWithLevel_Change(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(Level(false))
+	l := NewWithLevel(&buf, level)
+	l.Debug("hidden")
+	level.Set(Level(true))
+	l.Debug("shown")
+	if got := strings.Count(buf.String(), "\n"); got != 1 || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("log = %q, want only the record after the level change", buf.String())
+	}
+}
@@ -1,45 +1,72 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/KennyKur/CRUD_Todo/cache"
+	"github.com/KennyKur/CRUD_Todo/config"
 	_handler "github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/handler/grpchandler"
+	"github.com/KennyKur/CRUD_Todo/health"
+	"github.com/KennyKur/CRUD_Todo/idempotency"
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/metrics"
+	"github.com/KennyKur/CRUD_Todo/migrations"
+	"github.com/KennyKur/CRUD_Todo/ratelimit"
 	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tracing"
 	"github.com/KennyKur/CRUD_Todo/usecase"
 	_ "github.com/lib/pq"
 
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"google.golang.org/grpc"
 )
 
 type TodoHandler struct {
 	TodoUsecase _handler.TodoUsecaseInterface
 }
 
-func init() {
-	viper.SetConfigFile(`config.json`)
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic(err)
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(exitCode(err))
 	}
+}
 
-	if viper.GetBool(`debug`) {
-		log.Println("Service RUN on DEBUG mode")
+// serve runs the HTTP and gRPC servers until SIGINT or SIGTERM, applying
+// changes to the file loader read cfg from as they are made.
+func serve(loader *config.Loader, cfg *config.Config) {
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(logging.Level(cfg.Debug))
+	logger := logging.NewWithLevel(os.Stdout, logLevel)
+	slog.SetDefault(logger)
+	if cfg.Debug {
+		logger.Debug("Service RUN on DEBUG mode")
+	} else {
+		gin.SetMode(gin.ReleaseMode)
 	}
-}
 
-func main() {
-	r := gin.Default()
-	dbHost := viper.GetString(`database.host`)
-	dbPort := viper.GetString(`database.port`)
-	dbUser := viper.GetString(`database.user`)
-	dbPass := viper.GetString(`database.pass`)
-	dbName := viper.GetString(`database.name`)
-	connection := fmt.Sprintf("host=%s port=%s user=%s "+
-		"password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPass, dbName)
-	dbConn, err := sql.Open(`postgres`, connection)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	dbConfig := cfg.Database
+	dbConn, err := repository.OpenDB(dbConfig, dbConfig.Host, dbConfig.Port)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -54,9 +81,145 @@ func main() {
 			log.Fatal(err)
 		}
 	}()
-	repoTodo := repository.NewTodoRepository(dbConn)
-	usecaseTodo := usecase.NewTodoUsecase(repoTodo)
-	api := r.Group("/v1")
-	_handler.NewTodoHandler(api, usecaseTodo)
-	r.Run()
+	if dbConfig.MigrateOnStart {
+		applied, err := migrations.Up(context.Background(), dbConn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) > 0 {
+			logger.Info("migrations applied", "versions", applied)
+		}
+	}
+	metrics.RegisterDB(reg, dbConn, dbConfig.Name)
+	// Replicas are not pinged here: one that is down only sends reads to
+	// the primary until it is back.
+	replicas := repository.NewReplicaSet(dbConn, time.Duration(dbConfig.ReplicaMaxLagMs)*time.Millisecond)
+	for _, rc := range dbConfig.Replicas {
+		db, err := repository.OpenDB(dbConfig, rc.Host, rc.Port)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+		name := rc.Host + ":" + rc.Port
+		replicas.Add(name, db)
+		metrics.RegisterDB(reg, db, dbConfig.Name+"@"+name)
+	}
+	isolation, err := repository.ParseIsolation(dbConfig.Isolation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	txManager := repository.NewTxManager(dbConn, isolation, dbConfig.TxMaxAttempts)
+	repoTodo := repository.NewTodoRepository(dbConn, replicas)
+	if cfg.Cache.Enabled {
+		repoTodo = cache.NewTodoRepository(repoTodo, cache.NewMemoryStore(cfg.Cache.MaxEntries),
+			time.Duration(cfg.Cache.TTL)*time.Second, reg)
+	}
+	repoShare := repository.NewShareRepository(dbConn)
+	limitStore := ratelimit.NewMemoryStore()
+	quota := ratelimit.NewQuota(limitStore, cfg.RateLimit.DailyQuota)
+	usecaseTodo := metrics.NewTodoUsecase(tracing.NewTodoUsecase(usecase.NewTodoUsecase(repoTodo, repoShare, txManager, quota)), reg)
+	repoWebhook := repository.NewWebhookRepository(dbConn)
+	usecaseWebhook := usecase.NewWebhookUsecase(repoWebhook)
+	usecaseApiKey := usecase.NewApiKeyUsecase(repository.NewApiKeyRepository(dbConn))
+	usecaseShare := usecase.NewShareUsecase(repoShare, txManager)
+
+	r := gin.New()
+	tenants := tenant.NewRegistry(cfg.Tenancy.Tenants)
+	limits := ratelimit.NewLimits(cfg.RateLimit.Rules)
+	cors := _handler.NewCORS(cfg.CORS.AllowedOrigins)
+	loader.Watch(func(cfg *config.Config) {
+		logLevel.Set(logging.Level(cfg.Debug))
+		tenants.Set(cfg.Tenancy.Tenants)
+		limits.Set(cfg.RateLimit.Rules)
+		quota.Set(cfg.RateLimit.DailyQuota)
+		cors.Set(cfg.CORS.AllowedOrigins)
+	})
+
+	// CORS runs before Authenticate since preflight requests carry no
+	// credentials. Authenticate runs before the limits so they are counted
+	// per API key, and before ResolveTenant so a key cannot be used against
+	// another tenant. Replayed idempotent requests do not count against the
+	// limits.
+	r.Use(gin.Recovery(), tracing.Middleware(), _handler.RequestLogger(logger), metrics.Middleware(reg),
+		cors.Middleware(),
+		_handler.Authenticate(usecaseApiKey, cfg.Auth.Required),
+		_handler.ResolveTenant(tenants, cfg.Tenancy),
+		_handler.IdentifyClient(),
+		idempotency.Middleware(idempotency.NewMemoryStore(),
+			time.Duration(cfg.Idempotency.TTL)*time.Second, int64(cfg.Idempotency.MaxBodyBytes), _handler.ClientKey),
+		limits.Middleware(limitStore, _handler.ClientKey))
+	r.GET("/metrics", metrics.Handler(reg))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := usecase.NewWebhookDispatcher(repoWebhook,
+		time.Duration(cfg.Webhook.Timeout)*time.Second,
+		cfg.Webhook.MaxAttempts)
+	go dispatcher.Run(ctx, time.Duration(cfg.Webhook.Interval)*time.Second)
+	if len(dbConfig.Replicas) > 0 {
+		go replicas.Run(ctx, time.Duration(dbConfig.ReplicaCheckInterval)*time.Second,
+			time.Duration(cfg.Health.TimeoutMs)*time.Millisecond)
+	}
+
+	checkTimeout := time.Duration(cfg.Health.TimeoutMs) * time.Millisecond
+	checker := health.NewChecker(
+		health.Database(dbConn, checkTimeout),
+		health.Migrations(dbConn, checkTimeout),
+	)
+	go checker.Run(ctx, time.Duration(cfg.Health.Interval)*time.Second)
+	health.NewHandler(r, checker)
+
+	if err := registerRoutes(r, cfg, usecaseTodo, usecaseWebhook, usecaseApiKey, usecaseShare); err != nil {
+		log.Fatal(err)
+	}
+
+	// gRPC calls bypass the gin chain, also when multiplexed on the HTTP
+	// port, so the guard repeats its checks for them.
+	guard := &grpchandler.Guard{
+		ApiKeys:  usecaseApiKey,
+		Required: cfg.Auth.Required,
+		Tenants:  tenants,
+		Tenancy:  cfg.Tenancy,
+		Limits:   limits,
+		Store:    limitStore,
+	}
+	grpcServer := grpc.NewServer(guard.ServerOptions()...)
+	grpchandler.NewTodoServer(grpcServer, usecaseTodo)
+
+	srv := &http.Server{Addr: cfg.Server.Address, Handler: r}
+	if cfg.GRPC.SamePort {
+		srv.Handler = grpchandler.Multiplex(grpcServer, r)
+	} else {
+		lis, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// On SIGINT/SIGTERM report not ready first, give the orchestrator time
+	// to stop routing here, then let in-flight requests finish.
+	stop, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+	<-stop.Done()
+	checker.SetDraining(true)
+	drain := time.Duration(cfg.Server.DrainSeconds) * time.Second
+	logger.Info("draining", "wait", drain)
+	time.Sleep(drain)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("http shutdown", "err", err)
+	}
+	grpcServer.GracefulStop()
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/config"
+	"github.com/KennyKur/CRUD_Todo/handler/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// TestRoutesMatchOpenAPISpec fails when a route is registered without being
+// documented in handler/openapi/openapi.yaml, or the other way around.
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := registerRoutes(r, &config.Config{}, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		if !strings.HasPrefix(route.Path, "/v1/") && !strings.HasPrefix(route.Path, "/v2/") {
+			continue
+		}
+		registered[route.Method+" "+ginParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+
+	if len(registered) == 0 {
+		t.Fatal("no versioned routes registered")
+	}
+
+	var missing, stale []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	if len(missing) > 0 {
+		t.Errorf("routes missing from the OpenAPI spec: %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("OpenAPI operations without a route: %v", stale)
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := registerRoutes(r, &config.Config{}, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/openapi.json", "/docs", "/docs/swagger-ui/swagger-ui-bundle.js", "/docs/swagger-ui/swagger-ui.css"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusOK)
+		}
+		if path == "/docs" && strings.Contains(w.Body.String(), "https://") {
+			t.Errorf("GET /docs loads assets from another origin: %s", w.Body)
+		}
+	}
+}
+
+func TestV1RoutesDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := registerRoutes(r, &config.Config{}, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// the bodies fail validation, which still answers with the headers
+	for path, deprecated := range map[string]bool{"/v1/Todos": true, "/v1/Webhooks": false, "/v1/graphql": false} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		if got := w.Header().Get("Deprecation") != ""; got != deprecated {
+			t.Errorf("POST %s has Deprecation header = %v, want %v (status %d)", path, got, deprecated, w.Code)
+		}
+		if got := w.Header().Get("Sunset") != "" || w.Header().Get("Link") != ""; got && !deprecated {
+			t.Errorf("POST %s has Sunset or Link header (status %d)", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDB exports db.Stats() as go_sql_* metrics labelled with name:
+// open and in-use connections, and how often and how long callers waited
+// for one.
+func RegisterDB(reg prometheus.Registerer, db *sql.DB, name string) {
+	reg.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRegisterDB(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	reg := prometheus.NewRegistry()
+	RegisterDB(reg, db, "db_exercise")
+
+	for _, name := range []string{
+		"go_sql_open_connections",
+		"go_sql_in_use_connections",
+		"go_sql_wait_count_total",
+		"go_sql_wait_duration_seconds_total",
+	} {
+		if n := testutil.CollectAndCount(reg, name); n != 1 {
+			t.Errorf("%s series = %d, want 1", name, n)
+		}
+	}
+}
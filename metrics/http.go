@@ -0,0 +1,50 @@
+// Package metrics exposes Prometheus instrumentation for the HTTP layer, the
+// todo usecase and the database pool. Everything is added from the outside,
+// as middleware, decorators and collectors, so the instrumented code does not
+// know about it.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Middleware records the duration of every request, labelled by method,
+// route template and status. Requests that matched no route share the
+// "unmatched" route so the label stays bounded. Responses carrying a
+// Deprecation header are also counted in deprecated_calls_total.
+func Middleware(reg prometheus.Registerer) gin.HandlerFunc {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	deprecated := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "deprecated_calls_total",
+		Help: "Requests served by deprecated routes.",
+	}, []string{"method", "route"})
+	reg.MustRegister(duration, deprecated)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		duration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+		if c.Writer.Header().Get("Deprecation") != "" {
+			deprecated.WithLabelValues(c.Request.Method, route).Inc()
+		}
+	}
+}
+
+// Handler serves the metrics gathered by g.
+func Handler(g prometheus.Gatherer) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(Middleware(reg))
+	r.GET("/v2/todos/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/v1/Todo/:id", func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Status(http.StatusOK)
+	})
+	r.GET("/metrics", Handler(reg))
+
+	for _, path := range []string{"/v2/todos/1", "/v2/todos/2", "/nope", "/v1/Todo/1"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if n := testutil.CollectAndCount(reg, "http_request_duration_seconds"); n != 3 {
+		t.Errorf("series = %d, want 3 (one per route template)", n)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	r.ServeHTTP(w, req)
+	body := w.Body.String()
+	for _, s := range []string{
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_count{method="GET",route="/v2/todos/:id",status="404"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		`deprecated_calls_total{method="GET",route="/v1/Todo/:id"} 1`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("/metrics does not contain %q:\n%s", s, body)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type todoUsecase struct {
+	next  handler.TodoUsecaseInterface
+	calls *prometheus.CounterVec
+}
+
+// NewTodoUsecase wraps next and counts its calls by method and outcome:
+// success, not_found, invalid, forbidden or error.
+func NewTodoUsecase(next handler.TodoUsecaseInterface, reg prometheus.Registerer) handler.TodoUsecaseInterface {
+	calls := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "todo_usecase_calls_total",
+		Help: "Calls to the todo usecase by method and outcome.",
+	}, []string{"method", "outcome"})
+	reg.MustRegister(calls)
+	return &todoUsecase{next: next, calls: calls}
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, models.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, models.ErrInvalidTask):
+		return "invalid"
+	case errors.Is(err, models.ErrForbidden):
+		return "forbidden"
+	default:
+		return "error"
+	}
+}
+
+func (u *todoUsecase) observe(method string, err error) {
+	u.calls.WithLabelValues(method, outcome(err)).Inc()
+}
+
+func (u *todoUsecase) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	res, err := u.next.Fetch(ctx)
+	u.observe("Fetch", err)
+	return res, err
+}
+
+func (u *todoUsecase) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	res, err := u.next.FetchPage(ctx, filter, limit, offset)
+	u.observe("FetchPage", err)
+	return res, err
+}
+
+func (u *todoUsecase) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	res, err := u.next.GetByID(ctx, id)
+	u.observe("GetByID", err)
+	return res, err
+}
+
+func (u *todoUsecase) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	res, err := u.next.FetchByIDs(ctx, ids)
+	u.observe("FetchByIDs", err)
+	return res, err
+}
+
+func (u *todoUsecase) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	res, err := u.next.Create(ctx, todo)
+	u.observe("Create", err)
+	return res, err
+}
+
+func (u *todoUsecase) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	res, err := u.next.Update(ctx, todo, id)
+	u.observe("Update", err)
+	return res, err
+}
+
+func (u *todoUsecase) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	res, err := u.next.Patch(ctx, id, todo, fields)
+	u.observe("Patch", err)
+	return res, err
+}
+
+func (u *todoUsecase) Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	res, err := u.next.Merge(ctx, id, apply)
+	u.observe("Merge", err)
+	return res, err
+}
+
+func (u *todoUsecase) Delete(ctx context.Context, id int64) error {
+	err := u.next.Delete(ctx, id)
+	u.observe("Delete", err)
+	return err
+}
+
+func (u *todoUsecase) Watch(ctx context.Context) <-chan models.Todo_event {
+	u.observe("Watch", nil)
+	return u.next.Watch(ctx)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTodoUsecase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	reg := prometheus.NewRegistry()
+	u := NewTodoUsecase(mockUC, reg).(*todoUsecase)
+	ctx := context.Background()
+
+	mockUC.EXPECT().GetByID(ctx, int64(1)).Return(models.User_todo_list{ID: 1}, nil)
+	mockUC.EXPECT().GetByID(ctx, int64(2)).Return(models.User_todo_list{}, models.ErrNotFound)
+	mockUC.EXPECT().Create(ctx, gomock.Any()).Return(models.User_todo_list{}, models.ErrInvalidTask)
+	mockUC.EXPECT().Delete(ctx, int64(3)).Return(errors.New("connection refused"))
+
+	if res, _ := u.GetByID(ctx, 1); res.ID != 1 {
+		t.Errorf("GetByID() = %v, want the wrapped result", res)
+	}
+	u.GetByID(ctx, 2)
+	u.Create(ctx, models.User_todo_list{Task_name: "tidur"})
+	u.Delete(ctx, 3)
+
+	tests := []struct {
+		method, outcome string
+	}{
+		{"GetByID", "success"},
+		{"GetByID", "not_found"},
+		{"Create", "invalid"},
+		{"Delete", "error"},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(u.calls.WithLabelValues(tt.method, tt.outcome)); got != 1 {
+			t.Errorf("calls{%s,%s} = %v, want 1", tt.method, tt.outcome, got)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS user_todo_lists (
+    id        BIGSERIAL PRIMARY KEY,
+    task_name TEXT NOT NULL
+);
@@ -0,0 +1,13 @@
+ALTER TABLE user_todo_lists ADD COLUMN IF NOT EXISTS owner_id TEXT;
+CREATE INDEX IF NOT EXISTS user_todo_lists_owner_id_idx ON user_todo_lists (owner_id);
+
+CREATE TABLE IF NOT EXISTS todo_shares (
+    todo_id    BIGINT NOT NULL REFERENCES user_todo_lists (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    role       TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (todo_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS todo_shares_user_id_idx ON todo_shares (user_id);
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS user_id TEXT;
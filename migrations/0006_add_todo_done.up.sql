@@ -0,0 +1,2 @@
+-- todos are open until marked done with a PATCH
+ALTER TABLE user_todo_lists ADD COLUMN IF NOT EXISTS done BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,107 @@
+// Package migrations embeds the SQL schema files and applies them in
+// file-name order, recording each applied version in schema_migrations.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    TEXT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+
+// Versions lists every embedded migration, e.g. "0001_create_user_todo_lists",
+// in the order they are applied.
+func Versions() []string {
+	names, _ := fs.Glob(files, "*.up.sql")
+	sort.Strings(names)
+	versions := make([]string, len(names))
+	for i, name := range names {
+		versions[i] = strings.TrimSuffix(name, ".up.sql")
+	}
+	return versions
+}
+
+// Applied returns the versions recorded in schema_migrations. A database
+// that has never been migrated has none.
+func Applied(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return map[string]bool{}, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[string]bool{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// Pending returns the embedded versions that have not been applied yet.
+func Pending(ctx context.Context, db *sql.DB) ([]string, error) {
+	applied, err := Applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []string
+	for _, v := range Versions() {
+		if !applied[v] {
+			pending = append(pending, v)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the versions it applied.
+func Up(ctx context.Context, db *sql.DB) ([]string, error) {
+	if _, err := db.ExecContext(ctx, createTable); err != nil {
+		return nil, err
+	}
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	for _, v := range pending {
+		body, err := files.ReadFile(v + ".up.sql")
+		if err != nil {
+			return done, err
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return done, err
+		}
+		if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+			tx.Rollback()
+			return done, err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations(version) VALUES ($1)", v); err != nil {
+			tx.Rollback()
+			return done, err
+		}
+		if err := tx.Commit(); err != nil {
+			return done, err
+		}
+		done = append(done, v)
+	}
+	return done, nil
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestVersions(t *testing.T) {
+	want := []string{"0001_create_user_todo_lists", "0002_create_webhooks"}
+	if got := Versions(); !reflect.DeepEqual(got[:2], want) {
+		t.Errorf("Versions() = %v, want prefix %v", got, want)
+	}
+}
+
+func TestPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations')")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	got, err := Pending(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, Versions()) {
+		t.Errorf("Pending() on a fresh database = %v, want all %v", got, Versions())
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations')")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, v := range Versions() {
+		rows.AddRow(v)
+	}
+	mock.ExpectQuery("SELECT version FROM schema_migrations").WillReturnRows(rows)
+	got, err = Pending(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Pending() after migrating = %v, want none", got)
+	}
+}
+
+func TestUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations')")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT version FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("0001_create_user_todo_lists"))
+	pending := Versions()[1:]
+	for _, v := range pending {
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE|ALTER").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(v).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	done, err := Up(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(done, pending) {
+		t.Errorf("Up() applied %v, want %v", done, pending)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// api keys for non-interactive access
+
+const (
+	ScopeTodosRead      = "todos:read"
+	ScopeTodosWrite     = "todos:write"
+	ScopeTodosDelete    = "todos:delete"
+	ScopeWebhooksManage = "webhooks:manage"
+	ScopeAPIKeysManage  = "apikeys:manage"
+)
+
+var Scopes = []string{
+	ScopeTodosRead,
+	ScopeTodosWrite,
+	ScopeTodosDelete,
+	ScopeWebhooksManage,
+	ScopeAPIKeysManage,
+}
+
+// Api_key is a stored key. Only Prefix and the hash of the full key are
+// kept; Key is set once, in the response to its creation. A key with a User
+// acts as that user and is subject to the todo sharing roles; a key without
+// one acts for a service and sees every todo its scopes allow.
+type Api_key struct {
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Tenant       string     `json:"tenant"`
+	User         string     `json:"user,omitempty"`
+	Prefix       string     `json:"prefix"`
+	Key          string     `json:"key,omitempty"`
+	Hash         string     `json:"-"`
+	Scopes       []string   `json:"scopes"`
+	Created_at   time.Time  `json:"created_at"`
+	Expires_at   *time.Time `json:"expires_at"`
+	Last_used_at *time.Time `json:"last_used_at"`
+	Revoked_at   *time.Time `json:"revoked_at"`
+}
+
+// HasScope reports whether the key grants scope.
+func (k Api_key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
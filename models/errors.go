@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// domain errors, mapped to transport status codes by the delivery layers
+
+var (
+	ErrNotFound    = errors.New("data tidak ditemukan")
+	ErrInvalidTask = errors.New("task tidak valid")
+	// ErrUnauthorized covers unknown, revoked and expired credentials alike.
+	ErrUnauthorized = errors.New("kredensial tidak valid")
+	ErrForbidden    = errors.New("akses ditolak")
+	ErrInvalidShare = errors.New("share tidak valid")
+	// ErrQuotaExceeded is matched by every *QuotaError.
+	ErrQuotaExceeded = errors.New("kuota harian sudah habis")
+)
+
+// QuotaError reports a daily quota that is used up until ResetAt.
+type QuotaError struct {
+	Limit   int64
+	ResetAt time.Time
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("kuota harian sebanyak %d todo sudah habis", e.Limit)
+}
+
+func (e *QuotaError) Is(target error) bool { return target == ErrQuotaExceeded }
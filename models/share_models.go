@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// roles a user can hold on a todo, from least to most privileged
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+var Roles = []string{RoleViewer, RoleEditor, RoleOwner}
+
+func roleRank(role string) int {
+	for i, r := range Roles {
+		if r == role {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func IsRole(role string) bool {
+	return roleRank(role) > 0
+}
+
+// RoleAtLeast reports whether role grants everything need does.
+func RoleAtLeast(role, need string) bool {
+	return roleRank(role) > 0 && roleRank(role) >= roleRank(need)
+}
+
+// Todo_share grants User a role on a todo owned by someone else.
+type Todo_share struct {
+	Todo_id    int64     `json:"todo_id"`
+	User       string    `json:"user"`
+	Role       string    `json:"role"`
+	Created_at time.Time `json:"created_at"`
+}
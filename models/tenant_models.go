@@ -0,0 +1,11 @@
+package models
+
+// Tenant is an organisation sharing the deployment. Every row belongs to
+// exactly one tenant and is only visible to requests resolved to it.
+type Tenant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Denylist holds task names the tenant rejects. Nil means the built-in
+	// list; an empty list rejects nothing.
+	Denylist []string `json:"denylist"`
+}
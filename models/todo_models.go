@@ -1,8 +1,37 @@
 package models
 
+import "time"
+
 // model or domain
 
 type User_todo_list struct {
 	ID        int64  `json:"id"`
 	Task_name string `json:"task_name"`
+	Done      bool   `json:"done"`
+	// Owner is the user who created the todo; empty for todos created
+	// without a user. Role is the caller's effective role on it.
+	Owner string `json:"owner,omitempty"`
+	Role  string `json:"role,omitempty"`
+}
+
+// Todo_page is one page of todos, ordered by id, with Total the number of
+// todos on every page.
+type Todo_page struct {
+	Todos []User_todo_list
+	Total int
+}
+
+// Todo_filter narrows the todos a page is taken from. Nil IDs and an empty
+// TaskNameContains, which matches case-insensitively, keep every todo.
+type Todo_filter struct {
+	IDs              []int64
+	TaskNameContains string
+}
+
+// Todo_event describes a mutation of a todo. It is the webhook payload and
+// the message streamed to watchers.
+type Todo_event struct {
+	Event       string         `json:"event"`
+	Occurred_at time.Time      `json:"occurred_at"`
+	Data        User_todo_list `json:"data"`
 }
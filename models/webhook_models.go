@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// webhook subscription and delivery domain
+
+const (
+	EventTodoCreated = "todo.created"
+	EventTodoUpdated = "todo.updated"
+	EventTodoDeleted = "todo.deleted"
+)
+
+var WebhookEvents = []string{
+	EventTodoCreated,
+	EventTodoUpdated,
+	EventTodoDeleted,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+type Webhook_subscription struct {
+	ID          int64     `json:"id"`
+	Url         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	Event_types []string  `json:"event_types"`
+	Active      bool      `json:"active"`
+	Created_at  time.Time `json:"created_at"`
+}
+
+type Webhook_delivery struct {
+	ID              int64     `json:"id"`
+	Subscription_id int64     `json:"subscription_id"`
+	Url             string    `json:"url"`
+	Secret          string    `json:"-"`
+	Event_type      string    `json:"event_type"`
+	Payload         string    `json:"payload"`
+	Status          string    `json:"status"`
+	Attempts        int       `json:"attempts"`
+	Next_attempt_at time.Time `json:"next_attempt_at"`
+	Last_error      string    `json:"last_error"`
+	Created_at      time.Time `json:"created_at"`
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const pruneEvery = 1024
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+type counter struct {
+	n         int64
+	expiresAt time.Time
+}
+
+// MemoryStore keeps buckets and counters in process. Each instance limits
+// independently, so use a shared Store when running several replicas.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	counters map[string]*counter
+	calls    int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  map[string]*bucket{},
+		counters: map[string]*counter{},
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+
+	burst := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	var d Decision
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = refill(1-b.tokens, limit.Rate)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = refill(burst-b.tokens, limit.Rate)
+	b.full = now.Add(d.Reset)
+	return d, nil
+}
+
+func refill(tokens, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, expiresAt time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.expiresAt) {
+		c = &counter{expiresAt: expiresAt}
+		s.counters[key] = c
+	}
+	c.n++
+	return c.n, nil
+}
+
+func (s *MemoryStore) Decr(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.counters[key]; ok && c.n > 0 {
+		c.n--
+	}
+	return nil
+}
+
+// prune drops full buckets and expired counters now and then; they are
+// indistinguishable from missing ones.
+func (s *MemoryStore) prune(now time.Time) {
+	s.calls++
+	if s.calls%pruneEvery != 0 {
+		return
+	}
+	for k, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, k)
+		}
+	}
+	for k, c := range s.counters {
+		if !now.Before(c.expiresAt) {
+			delete(s.counters, k)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Unix(1650000000, 0)
+
+	steps := []struct {
+		after       time.Duration
+		wantAllowed bool
+		wantRemain  int
+	}{
+		{0, true, 1},
+		{0, true, 0},
+		{0, false, 0},
+		{500 * time.Millisecond, false, 0},
+		{500 * time.Millisecond, true, 0},
+		{5 * time.Second, true, 1},
+	}
+	for i, st := range steps {
+		now = now.Add(st.after)
+		d, err := s.Take(ctx, "k", limit, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Allowed != st.wantAllowed || d.Remaining != st.wantRemain {
+			t.Errorf("step %d: allowed %v remaining %d, want %v %d", i, d.Allowed, d.Remaining, st.wantAllowed, st.wantRemain)
+		}
+		if !d.Allowed && d.RetryAfter <= 0 {
+			t.Errorf("step %d: RetryAfter = %v, want > 0", i, d.RetryAfter)
+		}
+	}
+}
+
+func TestMemoryStore_Counter(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		s.Incr(ctx, "k", now.Add(time.Hour))
+	}
+	s.Decr(ctx, "k")
+	if n, _ := s.Incr(ctx, "k", now.Add(time.Hour)); n != 3 {
+		t.Errorf("Incr() after Decr() = %d, want 3", n)
+	}
+	s.Incr(ctx, "old", now.Add(-time.Second))
+	if n, _ := s.Incr(ctx, "old", now.Add(time.Hour)); n != 1 {
+		t.Errorf("Incr() after expiry = %d, want 1", n)
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// CounterStore keeps counters that expire at a fixed time. Implementations
+// must be safe for concurrent use, and Incr and Decr must be atomic.
+type CounterStore interface {
+	Incr(ctx context.Context, key string, expiresAt time.Time) (int64, error)
+	Decr(ctx context.Context, key string) error
+}
+
+// Quota caps how many units each key may use per UTC day. Set replaces the
+// limit while requests are being served.
+type Quota struct {
+	store CounterStore
+	limit atomic.Int64
+	now   func() time.Time
+}
+
+func NewQuota(store CounterStore, limit int64) *Quota {
+	q := &Quota{store: store, now: time.Now}
+	q.Set(limit)
+	return q
+}
+
+// Set replaces the limit; 0 turns the quota off. Counts so far today still
+// apply.
+func (q *Quota) Set(limit int64) {
+	q.limit.Store(limit)
+}
+
+// Reserve uses up one unit of key's quota for today, counting it before
+// checking so concurrent callers cannot both take the last unit. It returns
+// a *models.QuotaError when none is left, and otherwise a refund for callers
+// whose work then fails. The quota is not enforced while the store fails.
+func (q *Quota) Reserve(ctx context.Context, key string) (refund func(), err error) {
+	limit := q.limit.Load()
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	now := q.now().UTC()
+	resetAt := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	counter := "quota|" + key + "|" + now.Format("2006-01-02")
+
+	used, err := q.store.Incr(ctx, counter, resetAt)
+	if err != nil {
+		logging.FromContext(ctx).Warn("quota store", slog.Any("err", err))
+		return func() {}, nil
+	}
+	refund = func() {
+		if err := q.store.Decr(context.WithoutCancel(ctx), counter); err != nil {
+			logging.FromContext(ctx).Warn("quota store", slog.Any("err", err))
+		}
+	}
+	if used > limit {
+		refund()
+		return nil, &models.QuotaError{Limit: limit, ResetAt: resetAt}
+	}
+	return refund, nil
+}
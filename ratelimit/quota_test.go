@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+func TestQuota_Reserve(t *testing.T) {
+	ctx := context.Background()
+	q := NewQuota(NewMemoryStore(), 2)
+	q.now = func() time.Time { return time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC) }
+
+	refund, err := q.Reserve(ctx, "default|kenny")
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if _, err := q.Reserve(ctx, "default|kenny"); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	_, err = q.Reserve(ctx, "default|kenny")
+	var quotaErr *models.QuotaError
+	if !errors.Is(err, models.ErrQuotaExceeded) || !errors.As(err, &quotaErr) ||
+		!quotaErr.ResetAt.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Reserve() past the quota error = %v, want a quota error until midnight", err)
+	}
+	if _, err := q.Reserve(ctx, "default|siti"); err != nil {
+		t.Errorf("Reserve() for another user error = %v", err)
+	}
+
+	refund()
+	if _, err := q.Reserve(ctx, "default|kenny"); err != nil {
+		t.Errorf("Reserve() after a refund error = %v", err)
+	}
+
+	q.Set(0)
+	if _, err := q.Reserve(ctx, "default|kenny"); err != nil {
+		t.Errorf("Reserve() without a quota error = %v", err)
+	}
+}
+
+func TestQuota_ReserveConcurrent(t *testing.T) {
+	q := NewQuota(NewMemoryStore(), 10)
+	var (
+		wg      sync.WaitGroup
+		granted atomic.Int64
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := q.Reserve(context.Background(), "default|kenny"); err == nil {
+				granted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := granted.Load(); n != 10 {
+		t.Errorf("granted = %d, want 10", n)
+	}
+}
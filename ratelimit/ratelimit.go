@@ -0,0 +1,147 @@
+// Package ratelimit throttles clients with token buckets and caps how many
+// todos each user may create per day. State lives behind small store
+// interfaces so several instances can share it; MemoryStore keeps it in
+// process.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second holding at
+// most Burst tokens. Each request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Decision is the outcome of taking a token.
+type Decision struct {
+	Allowed   bool
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next token when not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+// Rules maps routes to limits. Route keys are "<METHOD> <route template>",
+// e.g. "POST /v1/Todos", and are matched case-insensitively. Routes without
+// an entry use Default; a zero limit disables limiting.
+type Rules struct {
+	Default Limit
+	Routes  map[string]Limit
+}
+
+func routeKey(c *gin.Context) string {
+	return strings.ToLower(c.Request.Method + " " + c.FullPath())
+}
+
+// Limits holds the Rules a middleware enforces. Set replaces them while
+// requests are being served.
+type Limits struct {
+	rules atomic.Pointer[Rules]
+}
+
+func NewLimits(rules Rules) *Limits {
+	l := &Limits{}
+	l.Set(rules)
+	return l
+}
+
+// Set replaces the rules. Buckets already taken from keep their tokens.
+func (l *Limits) Set(rules Rules) {
+	routes := make(map[string]Limit, len(rules.Routes))
+	for k, limit := range rules.Routes {
+		routes[strings.ToLower(k)] = limit
+	}
+	l.rules.Store(&Rules{Default: rules.Default, Routes: routes})
+}
+
+func (l *Limits) limit(route string) Limit {
+	rules := l.rules.Load()
+	if limit, ok := rules.Routes[route]; ok {
+		return limit
+	}
+	return rules.Default
+}
+
+// Middleware rejects requests with 429 once the client identified by key
+// has used up the bucket for the route. Responses carry RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset, plus Retry-After when rejected.
+// Store errors let the request through.
+func Middleware(store Store, rules Rules, key func(*gin.Context) string) gin.HandlerFunc {
+	return NewLimits(rules).Middleware(store, key)
+}
+
+// Middleware is like the package Middleware, enforcing the current rules.
+func (l *Limits) Middleware(store Store, key func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := routeKey(c)
+		limit := l.limit(route)
+		if !limit.enabled() || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+		d, err := store.Take(c.Request.Context(), key(c)+"|"+route, limit, time.Now())
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Warn("rate limit store", slog.Any("err", err))
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		c.Header("RateLimit-Reset", seconds(d.Reset))
+		if !d.Allowed {
+			c.Header("Retry-After", seconds(d.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": fmt.Sprintf("terlalu banyak permintaan, coba lagi dalam %s detik", seconds(d.RetryAfter)),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Allow is Middleware for transports other than gin: it takes a token from
+// the bucket of key for route, a "<METHOD> <route>" key of the rules. Routes
+// without a limit and store errors are allowed.
+func (l *Limits) Allow(ctx context.Context, store Store, key, route string) Decision {
+	route = strings.ToLower(route)
+	limit := l.limit(route)
+	if !limit.enabled() {
+		return Decision{Allowed: true}
+	}
+	d, err := store.Take(ctx, key+"|"+route, limit, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Warn("rate limit store", slog.Any("err", err))
+		return Decision{Allowed: true}
+	}
+	return d
+}
+
+// seconds rounds d up to whole seconds, as the headers require.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
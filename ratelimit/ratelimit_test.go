@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(NewMemoryStore(), Rules{
+		Default: Limit{Rate: 100, Burst: 100},
+		Routes:  map[string]Limit{"post /v1/todos": {Rate: 0.001, Burst: 2}},
+	}, func(c *gin.Context) string { return c.GetHeader("X-Client") }))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/v1/Todos", ok)
+	r.GET("/v1/Todo/", ok)
+
+	do := func(method, path, client string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("X-Client", client)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := do(http.MethodPost, "/v1/Todos", "a")
+		if w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != wantRemaining {
+			t.Fatalf("request %d: status %d remaining %q", i, w.Code, w.Header().Get("RateLimit-Remaining"))
+		}
+	}
+	w := do(http.MethodPost, "/v1/Todos", "a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request status = %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get("RateLimit-Limit") != "2" {
+		t.Errorf("429 headers = %v", w.Header())
+	}
+	if w := do(http.MethodPost, "/v1/Todos", "b"); w.Code != http.StatusOK {
+		t.Errorf("other client status = %d, want its own bucket", w.Code)
+	}
+	if w := do(http.MethodGet, "/v1/Todo/", "a"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("other route status = %d limit %q, want the default limit", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestLimits_Set(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limits := NewLimits(Rules{Default: Limit{Rate: 0.001, Burst: 1}})
+	r := gin.New()
+	store := NewMemoryStore()
+	key := func(c *gin.Context) string { return "a" }
+	r.Use(limits.Middleware(store, key))
+	r.POST("/v2/todos", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	do := func() int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/v2/todos", nil)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	if do() != http.StatusCreated || do() != http.StatusTooManyRequests {
+		t.Fatal("initial limit not enforced")
+	}
+
+	limits.Set(Rules{Routes: map[string]Limit{"POST /v2/todos": {}}})
+	if code := do(); code != http.StatusCreated {
+		t.Fatalf("status after lifting the limit = %d, want 201", code)
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = "id, name, tenant_id, COALESCE(user_id, ''), prefix, hash, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+type ApiKeyRepository struct {
+	Conn *sql.DB
+}
+
+func NewApiKeyRepository(Conn *sql.DB) usecase.ApiKeyRepositoryInterface {
+	return &ApiKeyRepository{Conn}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanApiKey(row rowScanner) (models.Api_key, error) {
+	var k models.Api_key
+	err := row.Scan(&k.ID, &k.Name, &k.Tenant, &k.User, &k.Prefix, &k.Hash, pq.Array(&k.Scopes),
+		&k.Created_at, &k.Expires_at, &k.Last_used_at, &k.Revoked_at)
+	return k, err
+}
+
+func (m *ApiKeyRepository) Fetch(ctx context.Context) (res []models.Api_key, err error) {
+	defer logDBError(ctx, "api_key.fetch", &err)
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE tenant_id = $1 ORDER BY id",
+		tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var keys []models.Api_key
+	for rows.Next() {
+		k, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// GetByPrefix looks a key up in every tenant: the key decides the tenant of
+// the request, not the other way around.
+func (m *ApiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (res models.Api_key, err error) {
+	defer logDBError(ctx, "api_key.get_by_prefix", &err)
+	k, err := scanApiKey(conn(ctx, m.Conn).QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix))
+	if err == sql.ErrNoRows {
+		return models.Api_key{}, models.ErrNotFound
+	}
+	return k, err
+}
+
+func (m *ApiKeyRepository) Create(ctx context.Context, key models.Api_key) (res models.Api_key, err error) {
+	defer logDBError(ctx, "api_key.create", &err)
+	row := conn(ctx, m.Conn).QueryRowContext(ctx, `INSERT INTO api_keys(name, tenant_id, user_id, prefix, hash, scopes, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7) RETURNING id, created_at`,
+		key.Name, tenant.FromContext(ctx).ID, key.User, key.Prefix, key.Hash, pq.Array(key.Scopes), key.Expires_at)
+	key.Tenant = tenant.FromContext(ctx).ID
+	if err := row.Scan(&key.ID, &key.Created_at); err != nil {
+		return models.Api_key{}, err
+	}
+	return key, nil
+}
+
+// Revoke marks the key revoked. Revoking twice keeps the first timestamp.
+func (m *ApiKeyRepository) Revoke(ctx context.Context, id int64, at time.Time) (err error) {
+	defer logDBError(ctx, "api_key.revoke", &err)
+	res, err := conn(ctx, m.Conn).ExecContext(ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2 AND tenant_id = $3",
+		at, id, tenant.FromContext(ctx).ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+func (m *ApiKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) (err error) {
+	defer logDBError(ctx, "api_key.touch", &err)
+	_, err = conn(ctx, m.Conn).ExecContext(ctx, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", at, id)
+	return err
+}
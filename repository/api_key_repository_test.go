@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestApiKeyRepository_Create(t *testing.T) {
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := models.Api_key{
+		Name:   "ci",
+		Prefix: "0123abcd",
+		Hash:   "hash",
+		Scopes: []string{models.ScopeTodosRead},
+	}
+	want := key
+	want.ID = 7
+	want.Tenant = tenant.DefaultID
+	want.Created_at = created
+
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.Api_key
+		wantErr     bool
+	}{
+		{
+			name: "success to add key",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO api_keys").
+					WithArgs(key.Name, tenant.DefaultID, key.User, key.Prefix, key.Hash, sqlmock.AnyArg(), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, created))
+			},
+			wantRes: want,
+			wantErr: false,
+		},
+		{
+			name: "failed to add key",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO api_keys").
+					WillReturnError(fmt.Errorf("some error"))
+			},
+			wantRes: models.Api_key{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			m := &ApiKeyRepository{Conn: db}
+			gotRes, err := m.Create(context.Background(), key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApiKeyRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("ApiKeyRepository.Create() = %v, want %v", gotRes, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestApiKeyRepository_GetByPrefix(t *testing.T) {
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "name", "tenant_id", "user_id", "prefix", "hash", "scopes", "created_at", "expires_at", "last_used_at", "revoked_at"}
+
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.Api_key
+		wantErr     error
+	}{
+		{
+			name: "success to get key",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM api_keys WHERE prefix = $1")).
+					WithArgs("0123abcd").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(7, "ci", "acme", "budi", "0123abcd", "hash", "{todos:read,todos:write}", created, nil, nil, nil))
+			},
+			wantRes: models.Api_key{
+				ID:         7,
+				Name:       "ci",
+				Tenant:     "acme",
+				User:       "budi",
+				Prefix:     "0123abcd",
+				Hash:       "hash",
+				Scopes:     []string{models.ScopeTodosRead, models.ScopeTodosWrite},
+				Created_at: created,
+			},
+		},
+		{
+			name: "unknown prefix",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("FROM api_keys").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantRes: models.Api_key{},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			m := &ApiKeyRepository{Conn: db}
+			gotRes, err := m.GetByPrefix(context.Background(), "0123abcd")
+			if err != tt.wantErr {
+				t.Errorf("ApiKeyRepository.GetByPrefix() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("ApiKeyRepository.GetByPrefix() = %+v, want %+v", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
+
+func TestApiKeyRepository_Revoke(t *testing.T) {
+	at := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantErr     error
+	}{
+		{
+			name: "success to revoke key",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at").
+					WithArgs(at, int64(7), tenant.DefaultID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "unknown key",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE api_keys SET revoked_at").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			m := &ApiKeyRepository{Conn: db}
+			if err := m.Revoke(context.Background(), 7, at); err != tt.wantErr {
+				t.Errorf("ApiKeyRepository.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DBConfig is the database section of config.json. Durations are in
+// seconds; zero leaves the database/sql default in place.
+type DBConfig struct {
+	Host string
+	Port string
+	User string
+	Pass string
+	Name string
+
+	// SSLMode is one of disable, require, verify-ca and verify-full.
+	SSLMode     string `mapstructure:"sslmode"`
+	SSLRootCert string `mapstructure:"sslrootcert"`
+	SSLCert     string `mapstructure:"sslcert"`
+	SSLKey      string `mapstructure:"sslkey"`
+
+	MigrateOnStart bool `mapstructure:"migrate_on_start"`
+	// Isolation is parsed by ParseIsolation.
+	Isolation     string
+	TxMaxAttempts int `mapstructure:"tx_max_attempts"`
+
+	MaxOpenConns    int `mapstructure:"max_open_conns"`
+	MaxIdleConns    int `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime int `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime int `mapstructure:"conn_max_idle_time"`
+
+	// Replicas serve reads that tolerate lag, with the credentials, TLS
+	// and pool settings of the primary.
+	Replicas             []ReplicaConfig
+	ReplicaMaxLagMs      int `mapstructure:"replica_max_lag_ms"`
+	ReplicaCheckInterval int `mapstructure:"replica_check_interval"`
+}
+
+type ReplicaConfig struct {
+	Host string
+	Port string
+}
+
+var sslModes = map[string]bool{"disable": true, "require": true, "verify-ca": true, "verify-full": true}
+
+// DSN returns the connection string for the server at host and port.
+func (c DBConfig) DSN(host, port string) (string, error) {
+	mode := c.SSLMode
+	if mode == "" {
+		mode = "disable"
+	}
+	if !sslModes[mode] {
+		return "", fmt.Errorf("sslmode %q tidak didukung", c.SSLMode)
+	}
+	params := [][2]string{
+		{"host", host}, {"port", port}, {"user", c.User}, {"password", c.Pass}, {"dbname", c.Name},
+		{"sslmode", mode}, {"sslrootcert", c.SSLRootCert}, {"sslcert", c.SSLCert}, {"sslkey", c.SSLKey},
+	}
+	var b strings.Builder
+	for _, p := range params {
+		if p[1] == "" && p[0] != "password" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(p[0] + "=" + dsnValue(p[1]))
+	}
+	return b.String(), nil
+}
+
+// dsnValue quotes v when it is empty or holds characters that would end it.
+func dsnValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(v) + "'"
+}
+
+// OpenDB opens a pool to the server at host and port. Like sql.Open it
+// does not connect.
+func OpenDB(c DBConfig, host, port string) (*sql.DB, error) {
+	dsn, err := c.DSN(host, port)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetime) * time.Second)
+	}
+	if c.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(time.Duration(c.ConnMaxIdleTime) * time.Second)
+	}
+	return db, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import "testing"
+
+func TestDBConfig_DSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DBConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "tls disabled by default",
+			config: DBConfig{User: "postgres", Pass: "rahasia", Name: "db_exercise"},
+			want:   "host=db port=5432 user=postgres password=rahasia dbname=db_exercise sslmode=disable",
+		},
+		{
+			name: "certificates",
+			config: DBConfig{User: "postgres", Name: "db_exercise", SSLMode: "verify-full",
+				SSLRootCert: "/etc/ssl/ca.pem", SSLCert: "/etc/ssl/client.pem", SSLKey: "/etc/ssl/client.key"},
+			want: "host=db port=5432 user=postgres password='' dbname=db_exercise sslmode=verify-full " +
+				"sslrootcert=/etc/ssl/ca.pem sslcert=/etc/ssl/client.pem sslkey=/etc/ssl/client.key",
+		},
+		{
+			name:   "quoted password",
+			config: DBConfig{User: "postgres", Pass: `a b'c\`, Name: "db_exercise", SSLMode: "require"},
+			want:   `host=db port=5432 user=postgres password='a b\'c\\' dbname=db_exercise sslmode=require`,
+		},
+		{
+			name:    "unknown sslmode",
+			config:  DBConfig{SSLMode: "prefer"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.DSN("db", "5432")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenDB_Pool(t *testing.T) {
+	db, err := OpenDB(DBConfig{MaxOpenConns: 7, ConnMaxLifetime: 60}, "localhost", "5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", got)
+	}
+
+	if _, err := OpenDB(DBConfig{SSLMode: "prefer"}, "localhost", "5432"); err == nil {
+		t.Error("OpenDB() with unknown sslmode error = nil")
+	}
+}
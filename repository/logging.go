@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// logDBError logs *err with the request logger carried by ctx. Domain errors
+// are expected outcomes and are left to the caller.
+func logDBError(ctx context.Context, op string, err *error) {
+	if *err == nil || errors.Is(*err, models.ErrNotFound) || errors.Is(*err, models.ErrInvalidTask) {
+		return
+	}
+	logging.FromContext(ctx).Error("database error", "op", op, "err", *err)
+}
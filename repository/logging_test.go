@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestTodoRepository_LogsDBErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), logging.New(&buf, false).With("request_id", "req-1"))
+	m := &TodoRepository{Conn: db}
+
+	mock.ExpectQuery("FROM user_todo_lists").WillReturnError(sql.ErrNoRows)
+	if _, err := m.GetByID(ctx, 1); err == nil {
+		t.Fatal("GetByID() error = nil")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("not found was logged: %s", buf.String())
+	}
+
+	mock.ExpectQuery("FROM user_todo_lists").WillReturnError(fmt.Errorf("connection refused"))
+	if _, err := m.Fetch(ctx); err == nil {
+		t.Fatal("Fetch() error = nil")
+	}
+	for _, want := range []string{`"request_id":"req-1"`, `"op":"todo.fetch"`, `"err":"connection refused"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log %s does not contain %s", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+)
+
+// lagQuery returns how far a standby's replay trails the primary, in
+// seconds, and 0 on a server that is not in recovery. While the primary
+// has no writes the replay timestamp stops moving, so an idle standby
+// looks lagged and reads go to the primary until the next write.
+const lagQuery = `SELECT CASE WHEN pg_is_in_recovery()
+	THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	ELSE 0 END`
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// ReplicaSet routes reads to read replicas that answer and are within
+// maxLag of the primary, falling back to the primary when none are.
+// Replicas start out unhealthy until the first check.
+type ReplicaSet struct {
+	primary  *sql.DB
+	maxLag   time.Duration
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+func NewReplicaSet(primary *sql.DB, maxLag time.Duration) *ReplicaSet {
+	return &ReplicaSet{primary: primary, maxLag: maxLag}
+}
+
+// Add registers db as a replica; name identifies it in logs.
+func (s *ReplicaSet) Add(name string, db *sql.DB) {
+	s.replicas = append(s.replicas, &replica{name: name, db: db})
+}
+
+// Reader returns the next healthy replica in turn, or the primary.
+func (s *ReplicaSet) Reader() *sql.DB {
+	n := len(s.replicas)
+	start := s.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := s.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return s.primary
+}
+
+// CheckOnce checks every replica concurrently, each within timeout.
+func (s *ReplicaSet) CheckOnce(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, r := range s.replicas {
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			err := s.check(ctx, r, timeout)
+			if r.healthy.Swap(err == nil) == (err == nil) {
+				return
+			}
+			if err != nil {
+				logging.FromContext(ctx).Warn("replica removed from reads", "replica", r.name, "error", err)
+			} else {
+				logging.FromContext(ctx).Info("replica serving reads", "replica", r.name)
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+func (s *ReplicaSet) check(ctx context.Context, r *replica, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var lag float64
+	if err := r.db.QueryRowContext(ctx, lagQuery).Scan(&lag); err != nil {
+		return err
+	}
+	if s.maxLag > 0 {
+		if d := time.Duration(lag * float64(time.Second)); d > s.maxLag {
+			return fmt.Errorf("replica tertinggal %s dari primary", d.Round(time.Millisecond))
+		}
+	}
+	return nil
+}
+
+// Run checks the replicas immediately and then every interval until ctx
+// is done.
+func (s *ReplicaSet) Run(ctx context.Context, interval, timeout time.Duration) {
+	s.CheckOnce(ctx, timeout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckOnce(ctx, timeout)
+		}
+	}
+}
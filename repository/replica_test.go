@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func TestReplicaSet_Reader(t *testing.T) {
+	primary, _ := newMock(t)
+	fresh, freshMock := newMock(t)
+	lagging, laggingMock := newMock(t)
+	down, downMock := newMock(t)
+
+	s := NewReplicaSet(primary, time.Second)
+	if s.Reader() != primary {
+		t.Error("Reader() without replicas is not the primary")
+	}
+	s.Add("fresh", fresh)
+	s.Add("lagging", lagging)
+	s.Add("down", down)
+	if s.Reader() != primary {
+		t.Error("Reader() before the first check is not the primary")
+	}
+
+	lag := regexp.QuoteMeta(lagQuery)
+	freshMock.ExpectQuery(lag).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0.2))
+	laggingMock.ExpectQuery(lag).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(30.0))
+	downMock.ExpectQuery(lag).WillReturnError(errors.New("connection refused"))
+	s.CheckOnce(context.Background(), time.Second)
+	for i := 0; i < 3; i++ {
+		if got := s.Reader(); got != fresh {
+			t.Fatalf("Reader() = %p, want the fresh replica %p", got, fresh)
+		}
+	}
+
+	freshMock.ExpectQuery(lag).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(5.0))
+	laggingMock.ExpectQuery(lag).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(30.0))
+	downMock.ExpectQuery(lag).WillReturnError(errors.New("connection refused"))
+	s.CheckOnce(context.Background(), time.Second)
+	if s.Reader() != primary {
+		t.Error("Reader() with every replica lagging or down is not the primary")
+	}
+	for _, m := range []sqlmock.Sqlmock{freshMock, laggingMock, downMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	}
+}
+
+func TestTodoRepository_ReadsFromReplica(t *testing.T) {
+	primary, primaryMock := newMock(t)
+	replica, replicaMock := newMock(t)
+	s := NewReplicaSet(primary, time.Second)
+	s.Add("replica", replica)
+	replicaMock.ExpectQuery(regexp.QuoteMeta(lagQuery)).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0))
+	s.CheckOnce(context.Background(), time.Second)
+	m := NewTodoRepository(primary, s)
+	ctx := context.Background()
+
+	replicaMock.ExpectQuery("SELECT (.+) FROM user_todo_lists").WithArgs(tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily", "", false))
+	replicaMock.ExpectQuery("SELECT (.+) FROM user_todo_lists").WithArgs(int64(1), tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily", "", false))
+	if _, err := m.Fetch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes, and reads inside a transaction, stay on the primary.
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectQuery("SELECT (.+) FROM user_todo_lists").WithArgs(int64(1), tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily", "", false))
+	primaryMock.ExpectExec("DELETE FROM user_todo_lists").WithArgs(int64(1), tenant.DefaultID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	primaryMock.ExpectCommit()
+	err := NewTxManager(primary, sql.LevelDefault, 1).WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := m.GetByID(ctx, 1); err != nil {
+			return err
+		}
+		return m.Delete(ctx, 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mock := range []sqlmock.Sqlmock{primaryMock, replicaMock} {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tracing"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/lib/pq"
+)
+
+// effectiveRole is the caller's role on t: owner of the todos they
+// created, else whatever s grants. $1 is the user.
+const effectiveRole = "CASE WHEN t.owner_id = $1 THEN 'owner' ELSE s.role END"
+
+const accessibleFrom = `FROM user_todo_lists t
+	LEFT JOIN todo_shares s ON s.todo_id = t.id AND s.user_id = $1`
+
+type ShareRepository struct {
+	Conn *sql.DB
+}
+
+func NewShareRepository(Conn *sql.DB) usecase.ShareRepositoryInterface {
+	return &ShareRepository{Conn}
+}
+
+// accessibleWhere keeps the todos $1 owns or has been shared in tenant $2.
+const accessibleWhere = `
+	WHERE t.tenant_id = $2 AND (t.owner_id = $1 OR s.role IS NOT NULL)`
+
+const accessibleSelect = "SELECT t.id, t.task_name, COALESCE(t.owner_id, ''), t.done, " + effectiveRole + " " +
+	accessibleFrom
+
+const accessibleTodos = accessibleSelect + accessibleWhere
+
+// FetchAccessible returns the todos user owns or has been shared, with
+// Role set to the user's effective role.
+func (m *ShareRepository) FetchAccessible(ctx context.Context, user string) (res []models.User_todo_list, err error) {
+	defer logDBError(ctx, "share.fetch_accessible", &err)
+	const query = accessibleTodos + " ORDER BY t.id"
+	return queryAccessible(ctx, conn(ctx, m.Conn), query, user, tenant.FromContext(ctx).ID)
+}
+
+// FetchAccessiblePage is FetchAccessible filtered and a page at a time, like
+// TodoRepository.FetchPage.
+func (m *ShareRepository) FetchAccessiblePage(ctx context.Context, user string, filter models.Todo_filter, limit, offset int) (res models.Todo_page, err error) {
+	defer logDBError(ctx, "share.fetch_accessible_page", &err)
+	where, args := filterWhere(accessibleWhere, "t.", filter, user, tenant.FromContext(ctx).ID)
+	q := conn(ctx, m.Conn)
+	if res.Total, err = countRows(ctx, q, "SELECT COUNT(*) "+accessibleFrom+where, args...); err != nil || offset >= res.Total {
+		return res, err
+	}
+	query := fmt.Sprintf("%s%s ORDER BY t.id LIMIT $%d OFFSET $%d", accessibleSelect, where, len(args)+1, len(args)+2)
+	res.Todos, err = queryAccessible(ctx, q, query, append(args, pageLimit(limit), offset)...)
+	return res, err
+}
+
+// queryAccessible runs a query selecting accessibleTodos and scans every row.
+func queryAccessible(ctx context.Context, q querier, query string, args ...interface{}) (res []models.User_todo_list, err error) {
+	ctx, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	defer func() { tracing.EndSQL(span, int64(len(res)), err) }()
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var todos []models.User_todo_list
+	for rows.Next() {
+		var todo models.User_todo_list
+		if err = rows.Scan(&todo.ID, &todo.Task_name, &todo.Owner, &todo.Done, &todo.Role); err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	return todos, rows.Err()
+}
+
+// Roles returns user's effective role on each of ids. Todos that do not
+// exist or that user cannot access are left out.
+func (m *ShareRepository) Roles(ctx context.Context, user string, ids []int64) (res map[int64]string, err error) {
+	defer logDBError(ctx, "share.roles", &err)
+	const query = "SELECT t.id, " + effectiveRole + " " + accessibleFrom + `
+	WHERE t.id = ANY($2) AND t.tenant_id = $3 AND (t.owner_id = $1 OR s.role IS NOT NULL)`
+	ctx, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	defer func() { tracing.EndSQL(span, int64(len(res)), err) }()
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, query, user, pq.Array(ids), tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	roles := map[int64]string{}
+	for rows.Next() {
+		var (
+			id   int64
+			role string
+		)
+		if err = rows.Scan(&id, &role); err != nil {
+			return nil, err
+		}
+		roles[id] = role
+	}
+	return roles, rows.Err()
+}
+
+func (m *ShareRepository) Fetch(ctx context.Context, todoID int64) (res []models.Todo_share, err error) {
+	defer logDBError(ctx, "share.fetch", &err)
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, `SELECT todo_id, user_id, role, created_at FROM todo_shares
+		WHERE todo_id = $1 AND tenant_id = $2 ORDER BY user_id`, todoID, tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var shares []models.Todo_share
+	for rows.Next() {
+		var share models.Todo_share
+		if err = rows.Scan(&share.Todo_id, &share.User, &share.Role, &share.Created_at); err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// Create grants every share in one transaction, replacing the role of
+// users the todo is already shared with. It fails with models.ErrNotFound
+// when one of the todos does not exist in the tenant.
+func (m *ShareRepository) Create(ctx context.Context, shares []models.Todo_share) (res []models.Todo_share, err error) {
+	defer logDBError(ctx, "share.create", &err)
+	err = inTx(ctx, m.Conn, func(ctx context.Context, q querier) error {
+		for _, share := range shares {
+			err := q.QueryRowContext(ctx, `INSERT INTO todo_shares(todo_id, user_id, role, tenant_id)
+				SELECT id, $2, $3, tenant_id FROM user_todo_lists WHERE id = $1 AND tenant_id = $4
+				ON CONFLICT (todo_id, user_id) DO UPDATE SET role = EXCLUDED.role RETURNING created_at`,
+				share.Todo_id, share.User, share.Role, tenant.FromContext(ctx).ID).Scan(&share.Created_at)
+			if err == sql.ErrNoRows {
+				return models.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+			res = append(res, share)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (m *ShareRepository) Delete(ctx context.Context, todoID int64, user string) (err error) {
+	defer logDBError(ctx, "share.delete", &err)
+	result, err := conn(ctx, m.Conn).ExecContext(ctx, "DELETE FROM todo_shares WHERE todo_id = $1 AND user_id = $2 AND tenant_id = $3",
+		todoID, user, tenant.FromContext(ctx).ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
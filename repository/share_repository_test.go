@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestShareRepository_FetchAccessible(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("FROM user_todo_lists t\\s+LEFT JOIN todo_shares s").
+		WithArgs("siti", tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done", "role"}).
+			AddRow(1, "Belajar", "siti", false, "owner").
+			AddRow(3, "daily", "budi", false, "viewer"))
+
+	m := &ShareRepository{Conn: db}
+	got, err := m.FetchAccessible(context.Background(), "siti")
+	if err != nil {
+		t.Fatalf("ShareRepository.FetchAccessible() error = %v", err)
+	}
+	want := []models.User_todo_list{
+		{ID: 1, Task_name: "Belajar", Owner: "siti", Role: models.RoleOwner},
+		{ID: 3, Task_name: "daily", Owner: "budi", Role: models.RoleViewer},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ShareRepository.FetchAccessible() = %v, want %v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShareRepository_FetchAccessiblePage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM user_todo_lists t\\s+LEFT JOIN todo_shares s.* AND t.task_name ILIKE \\$3$").
+		WithArgs("siti", tenant.DefaultID, "%dai%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("AND t.task_name ILIKE \\$3 ORDER BY t.id LIMIT \\$4 OFFSET \\$5").
+		WithArgs("siti", tenant.DefaultID, "%dai%", 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done", "role"}).
+			AddRow(3, "daily", "budi", false, "viewer"))
+
+	m := &ShareRepository{Conn: db}
+	got, err := m.FetchAccessiblePage(context.Background(), "siti", models.Todo_filter{TaskNameContains: "dai"}, 1, 2)
+	if err != nil {
+		t.Fatalf("ShareRepository.FetchAccessiblePage() error = %v", err)
+	}
+	want := models.Todo_page{
+		Todos: []models.User_todo_list{{ID: 3, Task_name: "daily", Owner: "budi", Role: models.RoleViewer}},
+		Total: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ShareRepository.FetchAccessiblePage() = %v, want %v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShareRepository_Roles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("WHERE t.id = ANY").
+		WithArgs("siti", sqlmock.AnyArg(), tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(1, "owner").AddRow(3, "editor"))
+
+	m := &ShareRepository{Conn: db}
+	got, err := m.Roles(context.Background(), "siti", []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("ShareRepository.Roles() error = %v", err)
+	}
+	want := map[int64]string{1: models.RoleOwner, 3: models.RoleEditor}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ShareRepository.Roles() = %v, want %v", got, want)
+	}
+}
+
+func TestShareRepository_Create(t *testing.T) {
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	shares := []models.Todo_share{
+		{Todo_id: 1, User: "siti", Role: models.RoleEditor},
+		{Todo_id: 2, User: "siti", Role: models.RoleEditor},
+	}
+
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     []models.Todo_share
+		wantErr     error
+	}{
+		{
+			name: "success to share group",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				for _, s := range shares {
+					mock.ExpectQuery("INSERT INTO todo_shares").
+						WithArgs(s.Todo_id, s.User, s.Role, tenant.DefaultID).
+						WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+				}
+				mock.ExpectCommit()
+			},
+			wantRes: []models.Todo_share{
+				{Todo_id: 1, User: "siti", Role: models.RoleEditor, Created_at: created},
+				{Todo_id: 2, User: "siti", Role: models.RoleEditor, Created_at: created},
+			},
+		},
+		{
+			name: "todo missing from tenant rolls back",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO todo_shares").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+				mock.ExpectQuery("INSERT INTO todo_shares").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "failed to share",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO todo_shares").
+					WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: fmt.Errorf("some error"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			m := &ShareRepository{Conn: db}
+			gotRes, err := m.Create(context.Background(), shares)
+			if fmt.Sprint(err) != fmt.Sprint(tt.wantErr) {
+				t.Errorf("ShareRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("ShareRepository.Create() = %v, want %v", gotRes, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
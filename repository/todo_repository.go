@@ -3,123 +3,307 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tracing"
 	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/lib/pq"
 )
 
+// list_not_todo is the denylist of tenants that do not configure their own.
 var list_not_todo = []string{
 	"cuti",
 	"berenang",
 	"tidur",
 }
 
+// deniedTask reports whether the tenant of ctx rejects name.
+func deniedTask(ctx context.Context, name string) bool {
+	denylist := tenant.FromContext(ctx).Denylist
+	if denylist == nil {
+		denylist = list_not_todo
+	}
+	for _, b := range denylist {
+		if b == name {
+			return true
+		}
+	}
+	return false
+}
+
+// todoSelect lists the columns scanned into a models.User_todo_list.
+const todoSelect = "id, task_name, COALESCE(owner_id, ''), done"
+
 type TodoRepository struct {
 	Conn *sql.DB
+	// Replicas serves Fetch and GetByID when set.
+	Replicas *ReplicaSet
 }
 
-func NewTodoRepository(Conn *sql.DB) usecase.TodoRepositoryInterface {
-	return &TodoRepository{Conn}
+func NewTodoRepository(Conn *sql.DB, replicas *ReplicaSet) usecase.TodoRepositoryInterface {
+	return &TodoRepository{Conn, replicas}
+}
+
+// reader is the connection for reads that tolerate replication lag: the
+// transaction ctx carries, else a replica when there is a healthy one.
+func (m *TodoRepository) reader(ctx context.Context) querier {
+	if m.Replicas == nil {
+		return conn(ctx, m.Conn)
+	}
+	return conn(ctx, m.Replicas.Reader())
 }
 
 func (m *TodoRepository) Fetch(ctx context.Context) (res []models.User_todo_list, err error) {
-	rows, err := m.Conn.Query("SELECT id, task_name FROM user_todo_lists")
+	defer logDBError(ctx, "todo.fetch", &err)
+	const query = "SELECT " + todoSelect + " FROM user_todo_lists WHERE tenant_id = $1 ORDER BY id"
+	return queryTodos(ctx, m.reader(ctx), query, tenant.FromContext(ctx).ID)
+}
+
+// FetchPage returns limit todos matching filter from offset on, or every
+// one from offset on when limit is 0, and how many match in all.
+func (m *TodoRepository) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (res models.Todo_page, err error) {
+	defer logDBError(ctx, "todo.fetch_page", &err)
+	where, args := filterWhere("WHERE tenant_id = $1", "", filter, tenant.FromContext(ctx).ID)
+	q := m.reader(ctx)
+	if res.Total, err = countRows(ctx, q, "SELECT COUNT(*) FROM user_todo_lists "+where, args...); err != nil || offset >= res.Total {
+		return res, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM user_todo_lists %s ORDER BY id LIMIT $%d OFFSET $%d", todoSelect, where, len(args)+1, len(args)+2)
+	res.Todos, err = queryTodos(ctx, q, query, append(args, pageLimit(limit), offset)...)
+	return res, err
+}
+
+// filterWhere adds the conditions of filter to where, on columns prefixed
+// with table, and returns it with their arguments appended to args.
+func filterWhere(where, table string, filter models.Todo_filter, args ...interface{}) (string, []interface{}) {
+	if filter.IDs != nil {
+		args = append(args, pq.Array(filter.IDs))
+		where += fmt.Sprintf(" AND %sid = ANY($%d)", table, len(args))
+	}
+	if filter.TaskNameContains != "" {
+		args = append(args, "%"+likeEscaper.Replace(filter.TaskNameContains)+"%")
+		where += fmt.Sprintf(" AND %stask_name ILIKE $%d", table, len(args))
+	}
+	return where, args
+}
+
+// likeEscaper makes the LIKE wildcards in a search string match themselves.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// queryTodos runs a query selecting todoSelect and scans every row.
+func queryTodos(ctx context.Context, q querier, query string, args ...interface{}) (res []models.User_todo_list, err error) {
+	_, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	defer func() { tracing.EndSQL(span, int64(len(res)), err) }()
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
-		return
+		return nil, err
 	}
+	defer rows.Close()
+
 	var todos []models.User_todo_list
 	for rows.Next() {
 		var todo models.User_todo_list
-		rows.Scan(&todo.ID, &todo.Task_name)
+		if err = rows.Scan(&todo.ID, &todo.Task_name, &todo.Owner, &todo.Done); err != nil {
+			return nil, err
+		}
 		todos = append(todos, todo)
 	}
+	return todos, rows.Err()
+}
 
-	defer rows.Close()
-	return todos, nil
+// countRows runs a SELECT COUNT(*) query.
+func countRows(ctx context.Context, q querier, query string, args ...interface{}) (n int, err error) {
+	_, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	err = q.QueryRowContext(ctx, query, args...).Scan(&n)
+	tracing.EndSQL(span, 1, err)
+	return n, err
+}
+
+// pageLimit is the LIMIT argument for limit: NULL, which is no limit, for 0.
+func pageLimit(limit int) interface{} {
+	if limit <= 0 {
+		return nil
+	}
+	return limit
 }
 
 func (m *TodoRepository) GetByID(ctx context.Context, id int64) (res models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.get", &err)
+	return getTodo(ctx, m.reader(ctx), getQuery, id)
+}
+
+// GetForUpdate reads the todo from the primary, or the transaction ctx
+// carries, and locks the row until that transaction ends.
+func (m *TodoRepository) GetForUpdate(ctx context.Context, id int64) (res models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.get_for_update", &err)
+	return getTodo(ctx, conn(ctx, m.Conn), getQuery+" FOR UPDATE", id)
+}
+
+const getQuery = "SELECT " + todoSelect + " FROM user_todo_lists WHERE id = $1 AND tenant_id = $2"
+
+func getTodo(ctx context.Context, q querier, query string, id int64) (models.User_todo_list, error) {
 	var todo models.User_todo_list
-	row := m.Conn.QueryRow("SELECT id, task_name FROM user_todo_lists WHERE id = $1", id)
-	err = row.Scan(&todo.ID, &todo.Task_name)
+	_, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	row := q.QueryRowContext(ctx, query, id, tenant.FromContext(ctx).ID)
+	err := row.Scan(&todo.ID, &todo.Task_name, &todo.Owner, &todo.Done)
+	if err == sql.ErrNoRows {
+		tracing.EndSQL(span, 0, nil)
+		return todo, models.ErrNotFound
+	}
 	if err != nil {
-		return
+		tracing.EndSQL(span, 0, err)
+		return models.User_todo_list{}, err
 	}
+	tracing.EndSQL(span, 1, nil)
 	return todo, nil
 }
 
-func (m TodoRepository) Create(ctx context.Context, todo models.User_todo_list) error {
-	tx, err := m.Conn.Begin()
-	for _, b := range list_not_todo {
-		if b == todo.Task_name {
-			err := errors.New("task tidak valid")
-			return err
+func (m *TodoRepository) FetchByIDs(ctx context.Context, ids []int64) (res []models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.fetch_by_ids", &err)
+	const query = "SELECT " + todoSelect + " FROM user_todo_lists WHERE id = ANY($1) AND tenant_id = $2"
+	ctx, span := tracing.StartSQL(ctx, "SELECT user_todo_lists", query)
+	defer func() { tracing.EndSQL(span, int64(len(res)), err) }()
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, query, pq.Array(ids), tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var todos []models.User_todo_list
+	for rows.Next() {
+		var todo models.User_todo_list
+		if err = rows.Scan(&todo.ID, &todo.Task_name, &todo.Owner, &todo.Done); err != nil {
+			return nil, err
 		}
+		todos = append(todos, todo)
 	}
-	if err != nil {
-		return err
+	return todos, rows.Err()
+}
+
+func (m TodoRepository) Create(ctx context.Context, todo models.User_todo_list) (res models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.create", &err)
+	if deniedTask(ctx, todo.Task_name) {
+		return models.User_todo_list{}, models.ErrInvalidTask
 	}
-	{
-		stmt, err := tx.Prepare("INSERT INTO user_todo_lists(task_name) VALUES ($1)")
+	err = inTx(ctx, m.Conn, func(ctx context.Context, q querier) error {
+		const query = "INSERT INTO user_todo_lists(task_name, owner_id, tenant_id) VALUES ($1, NULLIF($2, ''), $3) RETURNING " + todoSelect
+		sqlCtx, span := tracing.StartSQL(ctx, "INSERT user_todo_lists", query)
+		err := q.QueryRowContext(sqlCtx, query, todo.Task_name, todo.Owner, tenant.FromContext(ctx).ID).Scan(&res.ID, &res.Task_name, &res.Owner, &res.Done)
 		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		defer stmt.Close()
-		if _, err := stmt.Exec(todo.Task_name); err != nil {
-			tx.Rollback()
+			tracing.EndSQL(span, 0, err)
 			return err
 		}
+		tracing.EndSQL(span, 1, nil)
+		return enqueueWebhook(ctx, q, models.EventTodoCreated, res)
+	})
+	if err != nil {
+		return models.User_todo_list{}, err
 	}
-	tx.Commit()
-	return nil
+	return res, nil
 }
-func (m *TodoRepository) Update(ctx context.Context, todo models.User_todo_list, id int64) error {
-	tx, err := m.Conn.Begin()
-	for _, b := range list_not_todo {
-		if b == todo.Task_name {
-			err := errors.New("task tidak valid")
-			return err
-		}
+
+func (m *TodoRepository) Update(ctx context.Context, todo models.User_todo_list, id int64) (res models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.update", &err)
+	if deniedTask(ctx, todo.Task_name) {
+		return models.User_todo_list{}, models.ErrInvalidTask
+	}
+	err = inTx(ctx, m.Conn, func(ctx context.Context, q querier) error {
+		const query = "UPDATE user_todo_lists SET task_name = $1 WHERE id = $2 AND tenant_id = $3 RETURNING " + todoSelect
+		return updateTodo(ctx, q, query, &res, todo.Task_name, id, tenant.FromContext(ctx).ID)
+	})
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	return res, nil
+}
+
+// updateTodo runs an UPDATE ... RETURNING todoSelect into res and queues
+// the webhook of the change. No matching row is models.ErrNotFound.
+func updateTodo(ctx context.Context, q querier, query string, res *models.User_todo_list, args ...interface{}) error {
+	sqlCtx, span := tracing.StartSQL(ctx, "UPDATE user_todo_lists", query)
+	err := q.QueryRowContext(sqlCtx, query, args...).Scan(&res.ID, &res.Task_name, &res.Owner, &res.Done)
+	if err == sql.ErrNoRows {
+		tracing.EndSQL(span, 0, nil)
+		return models.ErrNotFound
 	}
 	if err != nil {
+		tracing.EndSQL(span, 0, err)
 		return err
 	}
-	{
-		stmt, err := tx.Prepare("UPDATE user_todo_lists SET task_name = $1 WHERE id = $2")
-		if err != nil {
-			tx.Rollback()
-			return err
+	tracing.EndSQL(span, 1, nil)
+	return enqueueWebhook(ctx, q, models.EventTodoUpdated, *res)
+}
+
+// todoColumns lists the fields a partial update may change, keyed by their
+// JSON name, with the column they are stored in.
+var todoColumns = map[string]struct {
+	column string
+	value  func(models.User_todo_list) interface{}
+}{
+	"task_name": {"task_name", func(t models.User_todo_list) interface{} { return t.Task_name }},
+	"done":      {"done", func(t models.User_todo_list) interface{} { return t.Done }},
+}
+
+// Patch writes only the given fields of todo and returns the stored row.
+func (m *TodoRepository) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (res models.User_todo_list, err error) {
+	defer logDBError(ctx, "todo.patch", &err)
+	var (
+		set  []string
+		args []interface{}
+		seen = map[string]bool{}
+	)
+	for _, f := range fields {
+		col, ok := todoColumns[f]
+		if !ok {
+			return models.User_todo_list{}, fmt.Errorf("field %q tidak dapat diubah", f)
 		}
-		defer stmt.Close()
-		if _, err := stmt.Exec(todo.Task_name, id); err != nil {
-			tx.Rollback()
-			return err
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		if f == "task_name" && deniedTask(ctx, todo.Task_name) {
+			return models.User_todo_list{}, models.ErrInvalidTask
 		}
+		args = append(args, col.value(todo))
+		set = append(set, fmt.Sprintf("%s = $%d", col.column, len(args)))
 	}
-	tx.Commit()
-	return nil
-}
+	if len(set) == 0 {
+		return getTodo(ctx, conn(ctx, m.Conn), getQuery, id)
+	}
+	args = append(args, id, tenant.FromContext(ctx).ID)
+	query := fmt.Sprintf("UPDATE user_todo_lists SET %s WHERE id = $%d AND tenant_id = $%d RETURNING %s",
+		strings.Join(set, ", "), len(args)-1, len(args), todoSelect)
 
-func (m *TodoRepository) Delete(ctx context.Context, id int64) error {
-	tx, err := m.Conn.Begin()
+	err = inTx(ctx, m.Conn, func(ctx context.Context, q querier) error {
+		return updateTodo(ctx, q, query, &res, args...)
+	})
 	if err != nil {
-		return err
+		return models.User_todo_list{}, err
 	}
-	{
-		stmt, err := tx.Prepare("DELETE FROM user_todo_lists WHERE id = $1")
+	return res, nil
+}
+
+func (m *TodoRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer logDBError(ctx, "todo.delete", &err)
+	return inTx(ctx, m.Conn, func(ctx context.Context, q querier) error {
+		const query = "DELETE FROM user_todo_lists WHERE id = $1 AND tenant_id = $2"
+		sqlCtx, span := tracing.StartSQL(ctx, "DELETE user_todo_lists", query)
+		result, err := q.ExecContext(sqlCtx, query, id, tenant.FromContext(ctx).ID)
 		if err != nil {
-			tx.Rollback()
+			tracing.EndSQL(span, 0, err)
 			return err
 		}
-		defer stmt.Close()
-		if _, err := stmt.Exec(id); err != nil {
-			tx.Rollback()
+		n, err := result.RowsAffected()
+		tracing.EndSQL(span, n, err)
+		if err != nil {
 			return err
 		}
-	}
-	tx.Commit()
-	return nil
-
+		if n == 0 {
+			return models.ErrNotFound
+		}
+		return enqueueWebhook(ctx, q, models.EventTodoDeleted, models.User_todo_list{ID: id})
+	})
 }
@@ -3,15 +3,23 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"reflect"
 	"regexp"
 	"testing"
 
 	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
+const outboxQuery = "INSERT INTO webhook_deliveries"
+
+var errSQL = errors.New("some error")
+
 func TestTodoRepository_Fetch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -25,11 +33,11 @@ func TestTodoRepository_Fetch(t *testing.T) {
 			ID: 2, Task_name: "Sprint Test",
 		},
 	}
-	rows := sqlmock.NewRows([]string{"id", "task_name"}).
-		AddRow(mockTodo[0].ID, mockTodo[0].Task_name).
-		AddRow(mockTodo[1].ID, mockTodo[1].Task_name)
+	rows := sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).
+		AddRow(mockTodo[0].ID, mockTodo[0].Task_name, "", false).
+		AddRow(mockTodo[1].ID, mockTodo[1].Task_name, "", false)
 
-	query := "SELECT id, task_name FROM user_todo_lists"
+	query := "SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists"
 	type fields struct {
 		Conn *sql.DB
 	}
@@ -49,14 +57,18 @@ func TestTodoRepository_Fetch(t *testing.T) {
 			fields: fields{
 				Conn: db,
 			},
+			args: args{
+				ctx: context.Background(),
+			},
 			mockClosure: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(query).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(rows)
 			},
 			wantRes: mockTodo,
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			tt.mockClosure(mock)
@@ -80,6 +92,134 @@ func TestTodoRepository_Fetch(t *testing.T) {
 	}
 }
 
+func TestTodoRepository_FetchScanErrors(t *testing.T) {
+	const query = "SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists WHERE tenant_id = $1 ORDER BY id"
+	tests := []struct {
+		name string
+		rows *sqlmock.Rows
+	}{
+		{
+			name: "bad column",
+			rows: sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow("satu", "Belajar", "", false),
+		},
+		{
+			name: "broken result set",
+			rows: sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).
+				AddRow(1, "Belajar", "", false).
+				AddRow(2, "daily", "", false).RowError(1, errSQL),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tenant.DefaultID).WillReturnRows(tt.rows)
+			m := &TodoRepository{Conn: db}
+			if got, err := m.Fetch(context.Background()); err == nil {
+				t.Errorf("TodoRepository.Fetch() = %v, want an error", got)
+			}
+		})
+	}
+}
+
+func TestTodoRepository_FetchPage(t *testing.T) {
+	const (
+		count = "SELECT COUNT(*) FROM user_todo_lists WHERE tenant_id = $1"
+		query = "SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists WHERE tenant_id = $1 ORDER BY id LIMIT $2 OFFSET $3"
+	)
+	tests := []struct {
+		name          string
+		filter        models.Todo_filter
+		limit, offset int
+		mockClosure   func(mock sqlmock.Sqlmock)
+		wantRes       models.Todo_page
+		wantErr       error
+	}{
+		{
+			name:  "limit and offset go to the database",
+			limit: 2, offset: 1,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WithArgs(tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tenant.DefaultID, 2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).
+						AddRow(2, "daily", "", false).
+						AddRow(3, "Belajar", "budi", true))
+			},
+			wantRes: models.Todo_page{Todos: []models.User_todo_list{
+				{ID: 2, Task_name: "daily"},
+				{ID: 3, Task_name: "Belajar", Owner: "budi", Done: true},
+			}, Total: 4},
+		},
+		{
+			name: "no limit",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WithArgs(tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(tenant.DefaultID, nil, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily", "", false))
+			},
+			wantRes: models.Todo_page{Todos: []models.User_todo_list{{ID: 1, Task_name: "daily"}}, Total: 1},
+		},
+		{
+			name:   "filter goes to the database",
+			filter: models.Todo_filter{IDs: []int64{2, 5}, TaskNameContains: "50%_off"},
+			limit:  2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				const where = "WHERE tenant_id = $1 AND id = ANY($2) AND task_name ILIKE $3"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM user_todo_lists "+where)).
+					WithArgs(tenant.DefaultID, sqlmock.AnyArg(), `%50\%\_off%`).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists "+where+" ORDER BY id LIMIT $4 OFFSET $5")).
+					WithArgs(tenant.DefaultID, sqlmock.AnyArg(), `%50\%\_off%`, 2, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(5, "Diskon 50%_off", "", false))
+			},
+			wantRes: models.Todo_page{Todos: []models.User_todo_list{{ID: 5, Task_name: "Diskon 50%_off"}}, Total: 1},
+		},
+		{
+			name:  "offset past the end only counts",
+			limit: 2, offset: 4,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WithArgs(tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			wantRes: models.Todo_page{Total: 4},
+		},
+		{
+			name:  "count error",
+			limit: 2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(count)).WillReturnError(errSQL)
+			},
+			wantErr: errSQL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := &TodoRepository{Conn: db}
+			got, err := m.FetchPage(context.Background(), tt.filter, tt.limit, tt.offset)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TodoRepository.FetchPage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("TodoRepository.FetchPage() = %v, want %v", got, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
 func TestTodoRepository_GetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	var id int64 = 5
@@ -89,10 +229,10 @@ func TestTodoRepository_GetByID(t *testing.T) {
 	mockTodo := models.User_todo_list{
 		ID: 5, Task_name: "Belajar",
 	}
-	rows := sqlmock.NewRows([]string{"id", "task_name"}).
-		AddRow(mockTodo.ID, mockTodo.Task_name)
+	rows := sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).
+		AddRow(mockTodo.ID, mockTodo.Task_name, "", false)
 
-	query := "SELECT id, task_name FROM user_todo_lists WHERE id = $1"
+	query := "SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists WHERE id = $1 AND tenant_id = $2"
 	type fields struct {
 		Conn *sql.DB
 	}
@@ -119,7 +259,7 @@ func TestTodoRepository_GetByID(t *testing.T) {
 			},
 			mockClosure: func(mock sqlmock.Sqlmock, a args) {
 				mock.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs(a.id).
+					WithArgs(a.id, tenant.DefaultID).
 					WillReturnRows(rows)
 			},
 			wantRes: mockTodo,
@@ -127,6 +267,7 @@ func TestTodoRepository_GetByID(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			if err != nil {
@@ -154,271 +295,456 @@ func TestTodoRepository_GetByID(t *testing.T) {
 	}
 }
 
-func TestTodoRepository_Create(t *testing.T) {
+func TestTodoRepository_GetForUpdate(t *testing.T) {
 	db, mock, err := sqlmock.New()
-	data := models.User_todo_list{Task_name: "daily_harian"}
-	data2 := models.User_todo_list{Task_name: "tidur"}
-	query := "INSERT"
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
-	type fields struct {
-		Conn *sql.DB
+	defer db.Close()
+	query := "SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(int64(5), tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(5, "Belajar", "siti", true))
+	m := &TodoRepository{Conn: db}
+	got, err := m.GetForUpdate(context.Background(), 5)
+	if err != nil {
+		t.Fatal(err)
 	}
-	type args struct {
-		ctx  context.Context
-		todo models.User_todo_list
+	if want := (models.User_todo_list{ID: 5, Task_name: "Belajar", Owner: "siti", Done: true}); got != want {
+		t.Errorf("TodoRepository.GetForUpdate() = %v, want %v", got, want)
 	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(int64(9), tenant.DefaultID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}))
+	if _, err := m.GetForUpdate(context.Background(), 9); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("TodoRepository.GetForUpdate() error = %v, want %v", err, models.ErrNotFound)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTodoRepository_Create(t *testing.T) {
+	const query = "INSERT INTO user_todo_lists"
+	errCommit := errors.New("commit failed")
 	tests := []struct {
 		name        string
-		fields      fields
-		args        args
-		mockClosure func(mock sqlmock.Sqlmock, a args)
-		wantErr     bool
+		todo        models.User_todo_list
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.User_todo_list
+		wantErr     error
 	}{
 		{
 			name: "success to add data",
-			fields: fields{
-				Conn: db,
-			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data,
-			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			todo: models.User_todo_list{Task_name: "daily_harian", Owner: "kenny"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(query).
+					WithArgs("daily_harian", "kenny", tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily_harian", "kenny", false))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoCreated, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
-			wantErr: false,
+			wantRes: models.User_todo_list{ID: 1, Task_name: "daily_harian", Owner: "kenny"},
 		},
 		{
-			name: "failed to create data (invalid data)",
-			fields: fields{
-				Conn: db,
-			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data2,
-			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
-				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name).WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
-			},
-			wantErr: true,
+			name:        "failed to create data (invalid data)",
+			todo:        models.User_todo_list{Task_name: "tidur"},
+			mockClosure: func(mock sqlmock.Sqlmock) {},
+			wantErr:     models.ErrInvalidTask,
 		},
 		{
 			name: "failed to create data (query error)",
-			fields: fields{
-				Conn: db,
+			todo: models.User_todo_list{Task_name: "daily_harian"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).
+					WithArgs("daily_harian", "", tenant.DefaultID).
+					WillReturnError(errSQL)
+				mock.ExpectRollback()
 			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data,
+			wantErr: errSQL,
+		},
+		{
+			name: "failed to queue the webhook",
+			todo: models.User_todo_list{Task_name: "daily_harian"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).
+					WithArgs("daily_harian", "", tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily_harian", "", false))
+				mock.ExpectExec(outboxQuery).WillReturnError(errSQL)
+				mock.ExpectRollback()
 			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			wantErr: errSQL,
+		},
+		{
+			name: "failed commit is reported",
+			todo: models.User_todo_list{Task_name: "daily_harian"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name).
-					WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(query).
+					WithArgs("daily_harian", "", tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "daily_harian", "", false))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoCreated, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit().WillReturnError(errCommit)
 			},
-			wantErr: true,
+			wantErr: errCommit,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := TodoRepository{
-				Conn: tt.fields.Conn,
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 			}
-			tt.mockClosure(mock, tt.args)
-			if err := m.Create(tt.args.ctx, tt.args.todo); (err != nil) != tt.wantErr {
-				t.Errorf("TodoRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := TodoRepository{Conn: db}
+			gotRes, err := m.Create(context.Background(), tt.todo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TodoRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			mock.ExpectClose()
-			// Explicit closing instead of deferred in order to check ExpectationsWereMet
-			if err = db.Close(); err != nil {
-				t.Error(err)
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("TodoRepository.Create() = %v, want %v", gotRes, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
 			}
 		})
 	}
 }
 
 func TestTodoRepository_Update(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	data := models.User_todo_list{Task_name: "halo_bandung"}
-	data2 := models.User_todo_list{Task_name: "tidur"}
-	var id int64 = 2
-	query := "UPDATE"
-	type fields struct {
-		Conn *sql.DB
-	}
-	type args struct {
-		ctx  context.Context
-		todo models.User_todo_list
-		id   int64
-	}
+	const query = "UPDATE user_todo_lists"
 	tests := []struct {
 		name        string
-		fields      fields
-		args        args
-		mockClosure func(mock sqlmock.Sqlmock, a args)
-		wantErr     bool
+		todo        models.User_todo_list
+		id          int64
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.User_todo_list
+		wantErr     error
 	}{
 		{
 			name: "success update data",
-			fields: fields{
-				Conn: db,
-			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data,
-				id:   id,
-			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			todo: models.User_todo_list{Task_name: "halo_bandung"},
+			id:   2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name, a.id).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(query).
+					WithArgs("halo_bandung", int64(2), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(2, "halo_bandung", "kenny", false))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoUpdated, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
-			wantErr: false,
+			wantRes: models.User_todo_list{ID: 2, Task_name: "halo_bandung", Owner: "kenny"},
 		},
 		{
-			name: "failed update data (invalid data)",
-			fields: fields{
-				Conn: db,
-			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data2,
-				id:   id,
-			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			name: "missing row is not found",
+			todo: models.User_todo_list{Task_name: "halo_bandung"},
+			id:   999,
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name, a.id).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(query).
+					WithArgs("halo_bandung", int64(999), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}))
 				mock.ExpectRollback()
 			},
-			wantErr: true,
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:        "failed update data (invalid data)",
+			todo:        models.User_todo_list{Task_name: "tidur"},
+			id:          2,
+			mockClosure: func(mock sqlmock.Sqlmock) {},
+			wantErr: models.ErrInvalidTask,
 		},
 		{
 			name: "failed update data (sql error)",
-			fields: fields{
-				Conn: db,
+			todo: models.User_todo_list{Task_name: "halo_bandung"},
+			id:   2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).
+					WithArgs("halo_bandung", int64(2), tenant.DefaultID).WillReturnError(errSQL)
+				mock.ExpectRollback()
 			},
-			args: args{
-				ctx:  context.Background(),
-				todo: data,
-				id:   id,
+			wantErr: errSQL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := &TodoRepository{Conn: db}
+			got, err := m.Update(context.Background(), tt.todo, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TodoRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("TodoRepository.Update() = %v, want %v", got, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestTodoRepository_Patch(t *testing.T) {
+	tests := []struct {
+		name        string
+		todo        models.User_todo_list
+		fields      []string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.User_todo_list
+		wantErr     error
+	}{
+		{
+			name:   "only changed columns are written",
+			todo:   models.User_todo_list{Task_name: "halo_bandung"},
+			fields: []string{"task_name"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE user_todo_lists SET task_name = $1 WHERE id = $2 AND tenant_id = $3 RETURNING id, task_name, COALESCE(owner_id, '')")).
+					WithArgs("halo_bandung", int64(2), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(2, "halo_bandung", "", false))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoUpdated, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			wantRes: models.User_todo_list{ID: 2, Task_name: "halo_bandung"},
+		},
+		{
+			name:   "done is written on its own",
+			todo:   models.User_todo_list{Task_name: "daily", Done: true},
+			fields: []string{"done"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
-				mock.ExpectExec(query).
-					WithArgs(a.todo.Task_name, a.id).WillReturnError(fmt.Errorf("some error"))
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE user_todo_lists SET done = $1 WHERE id = $2 AND tenant_id = $3 RETURNING id, task_name, COALESCE(owner_id, ''), done")).
+					WithArgs(true, int64(2), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(2, "daily", "", true))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoUpdated, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantRes: models.User_todo_list{ID: 2, Task_name: "daily", Done: true},
+		},
+		{
+			name:   "no fields reads the current row",
+			fields: nil,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists")).
+					WithArgs(int64(2), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(2, "daily", "", false))
+			},
+			wantRes: models.User_todo_list{ID: 2, Task_name: "daily"},
+		},
+		{
+			name:        "invalid task",
+			todo:        models.User_todo_list{Task_name: "tidur"},
+			fields:      []string{"task_name"},
+			mockClosure: func(mock sqlmock.Sqlmock) {},
+			wantErr:     models.ErrInvalidTask,
+		},
+		{
+			name:   "missing row",
+			todo:   models.User_todo_list{Task_name: "halo_bandung"},
+			fields: []string{"task_name"},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE user_todo_lists").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}))
 				mock.ExpectRollback()
 			},
-			wantErr: true,
+			wantErr: models.ErrNotFound,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &TodoRepository{
-				Conn: tt.fields.Conn,
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
 			}
-			tt.mockClosure(mock, tt.args)
-			if err := m.Update(tt.args.ctx, tt.args.todo, tt.args.id); (err != nil) != tt.wantErr {
-				t.Errorf("TodoRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := &TodoRepository{Conn: db}
+			got, err := m.Patch(context.Background(), 2, tt.todo, tt.fields)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TodoRepository.Patch() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			mock.ExpectClose()
-			// Explicit closing instead of deferred in order to check ExpectationsWereMet
-			if err = db.Close(); err != nil {
-				t.Error(err)
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("TodoRepository.Patch() = %v, want %v", got, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
 			}
 		})
 	}
 }
 
 func TestTodoRepository_Delete(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	var id int64 = 2
-	query := "DELETE"
-	type fields struct {
-		Conn *sql.DB
-	}
-	type args struct {
-		ctx context.Context
-		id  int64
-	}
+	const query = "DELETE FROM user_todo_lists"
 	tests := []struct {
 		name        string
-		fields      fields
-		args        args
-		mockClosure func(mock sqlmock.Sqlmock, a args)
-		wantErr     bool
+		id          int64
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantErr     error
 	}{
 		{
 			name: "success delete data",
-			fields: fields{
-				Conn: db,
-			},
-			args: args{
-				ctx: context.Background(),
-				id:  id,
-			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			id:   2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
 				mock.ExpectExec(query).
-					WithArgs(a.id).WillReturnResult(sqlmock.NewResult(1, 1))
+					WithArgs(int64(2), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(outboxQuery).
+					WithArgs(models.EventTodoDeleted, sqlmock.AnyArg(), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
-			wantErr: false,
 		},
 		{
-			name: "failed to delete data",
-			fields: fields{
-				Conn: db,
+			name: "missing row is not found",
+			id:   999,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(int64(999), tenant.DefaultID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
-			args: args{
-				ctx: context.Background(),
-				id:  id,
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "failed to delete data",
+			id:   2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(int64(2), tenant.DefaultID).WillReturnError(errSQL)
+				mock.ExpectRollback()
 			},
-			mockClosure: func(mock sqlmock.Sqlmock, a args) {
+			wantErr: errSQL,
+		},
+		{
+			name: "rows affected unavailable",
+			id:   2,
+			mockClosure: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectPrepare(query)
 				mock.ExpectExec(query).
-					WithArgs(a.id).WillReturnError(fmt.Errorf("some error"))
+					WithArgs(int64(2), tenant.DefaultID).WillReturnResult(sqlmock.NewErrorResult(errSQL))
+				mock.ExpectRollback()
 			},
-			wantErr: true,
+			wantErr: errSQL,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := &TodoRepository{
-				Conn: tt.fields.Conn,
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 			}
-			tt.mockClosure(mock, tt.args)
-			if err := m.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := &TodoRepository{Conn: db}
+			if err := m.Delete(context.Background(), tt.id); !errors.Is(err, tt.wantErr) {
 				t.Errorf("TodoRepository.Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			mock.ExpectClose()
-			// Explicit closing instead of deferred in order to check ExpectationsWereMet
-			if err = db.Close(); err != nil {
-				t.Error(err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestTodoRepository_FetchByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).
+		AddRow(1, "Belajar", "", false).
+		AddRow(3, "daily", "budi", false)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists WHERE id = ANY($1) AND tenant_id = $2")).
+		WithArgs(sqlmock.AnyArg(), tenant.DefaultID).
+		WillReturnRows(rows)
+
+	m := &TodoRepository{Conn: db}
+	got, err := m.FetchByIDs(context.Background(), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("TodoRepository.FetchByIDs() error = %v", err)
+	}
+	want := []models.User_todo_list{{ID: 1, Task_name: "Belajar"}, {ID: 3, Task_name: "daily", Owner: "budi"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TodoRepository.FetchByIDs() = %v, want %v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTodoRepository_TracesStatements(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(prev)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_name, COALESCE(owner_id, ''), done FROM user_todo_lists")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(1, "Belajar", "", false).AddRow(2, "daily", "", false))
+
+	m := &TodoRepository{Conn: db}
+	if _, err := m.Fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if spans[0].Name() != "SELECT user_todo_lists" ||
+		attrs["db.query.text"] != "SELECT id, task_name, COALESCE(owner_id, ?), done FROM user_todo_lists WHERE tenant_id = $1 ORDER BY id" ||
+		attrs["db.response.rows"] != "2" {
+		t.Errorf("span = %s %v", spans[0].Name(), attrs)
+	}
+}
+
+func TestDeniedTask(t *testing.T) {
+	acme := tenant.NewContext(context.Background(), models.Tenant{ID: "acme", Denylist: []string{"rapat"}})
+	open := tenant.NewContext(context.Background(), models.Tenant{ID: "open", Denylist: []string{}})
+	tests := []struct {
+		name string
+		ctx  context.Context
+		task string
+		want bool
+	}{
+		{"built-in list without tenant", context.Background(), "tidur", true},
+		{"tenant list replaces built-in", acme, "tidur", false},
+		{"tenant list", acme, "rapat", true},
+		{"empty tenant list", open, "tidur", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deniedTask(tt.ctx, tt.task); got != tt.want {
+				t.Errorf("deniedTask(%q) = %v, want %v", tt.task, got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+type afterCommitKey struct{}
+
+// InTx reports whether ctx carries a transaction, whose reads may see
+// writes other connections cannot yet.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction ctx carries has committed, and
+// right away when ctx carries none. fn does not run on rollback.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*[]func())
+	if !ok {
+		fn()
+		return
+	}
+	*hooks = append(*hooks, fn)
+}
+
+// conn returns the transaction ctx carries, else db, so repository calls
+// made inside TxManager.WithinTx take part in its transaction.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx runs fn in the transaction ctx carries, or in one of its own that
+// is committed when fn succeeds. Repository methods writing several rows
+// use it so they stay atomic on their own and join a caller's unit of work.
+func inTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, q querier) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx, tx)
+	}
+	return runTx(ctx, db, nil, func(ctx context.Context) error {
+		return fn(ctx, conn(ctx, db))
+	})
+}
+
+// runTx runs fn in a new transaction, rolling it back when fn fails or
+// panics and committing it otherwise. AfterCommit hooks run once the
+// commit succeeds.
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(ctx context.Context) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	var hooks []func()
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			return
+		}
+		for _, hook := range hooks {
+			hook()
+		}
+	}()
+	ctx = context.WithValue(ctx, afterCommitKey{}, &hooks)
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// TxManager runs units of work spanning several repository calls in one
+// transaction.
+type TxManager struct {
+	db          *sql.DB
+	opts        sql.TxOptions
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewTxManager returns a TxManager beginning transactions at isolation and
+// running a unit of work up to maxAttempts times when it fails to serialize.
+func NewTxManager(db *sql.DB, isolation sql.IsolationLevel, maxAttempts int) *TxManager {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &TxManager{db: db, opts: sql.TxOptions{Isolation: isolation}, maxAttempts: maxAttempts, backoff: 10 * time.Millisecond}
+}
+
+// WithinTx runs fn in a transaction that repository calls made with the
+// context passed to fn take part in. The transaction is rolled back when fn
+// returns an error or panics and committed otherwise; a failed commit is
+// returned. Inside another WithinTx, fn joins the outer transaction and
+// only the outermost one is retried, so fn may run more than once and must
+// not have effects outside the database.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, m.db, &m.opts, fn)
+		if attempt >= m.maxAttempts || !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * m.backoff):
+		}
+	}
+}
+
+// retryable reports whether err is a serialization failure or a deadlock,
+// after which the transaction may succeed when run again.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+}
+
+// ParseIsolation maps an isolation level name such as "repeatable read"
+// to its sql.IsolationLevel; an empty name is the driver default.
+func ParseIsolation(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+	return sql.LevelDefault, fmt.Errorf("isolation level %q tidak dikenal", name)
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/lib/pq"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestTxManager_WithinTx(t *testing.T) {
+	errFn := errors.New("fn failed")
+	serialization := &pq.Error{Code: "40001"}
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		fn          func(attempt int, repo *TodoRepository) func(ctx context.Context) error
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantErr     error
+		wantRuns    int
+	}{
+		{
+			name:        "repository calls share one transaction",
+			maxAttempts: 1,
+			fn: func(_ int, repo *TodoRepository) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if _, err := repo.GetByID(ctx, 2); err != nil {
+						return err
+					}
+					return repo.Delete(ctx, 2)
+				}
+			},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM user_todo_lists").WithArgs(int64(2), tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "task_name", "owner_id", "done"}).AddRow(2, "daily", "", false))
+				mock.ExpectExec("DELETE FROM user_todo_lists").WithArgs(int64(2), tenant.DefaultID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(outboxQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			wantRuns: 1,
+		},
+		{
+			name:        "error rolls back",
+			maxAttempts: 3,
+			fn: func(_ int, repo *TodoRepository) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return repo.Delete(ctx, 9) }
+			},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM user_todo_lists").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr:  models.ErrNotFound,
+			wantRuns: 1,
+		},
+		{
+			name:        "failed commit is returned",
+			maxAttempts: 1,
+			fn: func(int, *TodoRepository) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return nil }
+			},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(errFn)
+			},
+			wantErr:  errFn,
+			wantRuns: 1,
+		},
+		{
+			name:        "serialization failure is retried",
+			maxAttempts: 3,
+			fn: func(attempt int, _ *TodoRepository) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if attempt == 1 {
+						return serialization
+					}
+					return nil
+				}
+			},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			wantRuns: 2,
+		},
+		{
+			name:        "retries are bounded",
+			maxAttempts: 2,
+			fn: func(int, *TodoRepository) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return serialization }
+			},
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			wantErr:  serialization,
+			wantRuns: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+			m := NewTxManager(db, sql.LevelDefault, tt.maxAttempts)
+			m.backoff = 0
+			repo := &TodoRepository{Conn: db}
+
+			runs := 0
+			err = m.WithinTx(context.Background(), func(ctx context.Context) error {
+				runs++
+				return tt.fn(runs, repo)(ctx)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WithinTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("fn ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestTxManager_WithinTx_Nested(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	m := NewTxManager(db, sql.LevelDefault, 1)
+	err = m.WithinTx(context.Background(), func(ctx context.Context) error {
+		return m.WithinTx(ctx, func(ctx context.Context) error { return nil })
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestTxManager_WithinTx_Panic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("panic was not propagated")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet expectations: %v", err)
+		}
+	}()
+	NewTxManager(db, sql.LevelDefault, 3).WithinTx(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	})
+}
+
+func TestAfterCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	m := NewTxManager(db, sql.LevelDefault, 1)
+
+	ran := 0
+	AfterCommit(context.Background(), func() { ran++ })
+	if ran != 1 {
+		t.Fatalf("hook outside a transaction ran %d times, want 1", ran)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	m.WithinTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran++ })
+		return errors.New("fn failed")
+	})
+	if ran != 1 {
+		t.Fatal("hook ran after a rollback")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	m.WithinTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran++ })
+		if ran != 1 {
+			t.Error("hook ran before the commit")
+		}
+		return nil
+	})
+	if ran != 2 {
+		t.Errorf("hook ran %d times after the commit, want once", ran-1)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestParseIsolation(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    sql.IsolationLevel
+		wantErr bool
+	}{
+		{"", sql.LevelDefault, false},
+		{"read committed", sql.LevelReadCommitted, false},
+		{"Repeatable Read", sql.LevelRepeatableRead, false},
+		{"serializable", sql.LevelSerializable, false},
+		{"snapshot", sql.LevelDefault, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseIsolation(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseIsolation(%q) = %v, %v, want %v, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tracing"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	Conn *sql.DB
+}
+
+func NewWebhookRepository(Conn *sql.DB) usecase.WebhookRepositoryInterface {
+	return &WebhookRepository{Conn}
+}
+
+// enqueueWebhook writes one outbox row per active subscription listening to
+// the event, inside the caller's transaction.
+func enqueueWebhook(ctx context.Context, q querier, event string, todo models.User_todo_list) error {
+	payload, err := json.Marshal(models.Todo_event{
+		Event:       event,
+		Occurred_at: time.Now().UTC(),
+		Data:        todo,
+	})
+	if err != nil {
+		return err
+	}
+	const query = `INSERT INTO webhook_deliveries(subscription_id, event_type, payload, tenant_id)
+		SELECT id, $1, $2, tenant_id FROM webhook_subscriptions
+		WHERE active AND $1 = ANY(event_types) AND tenant_id = $3`
+	sqlCtx, span := tracing.StartSQL(ctx, "INSERT webhook_deliveries", query)
+	result, err := q.ExecContext(sqlCtx, query, event, string(payload), tenant.FromContext(ctx).ID)
+	var n int64
+	if err == nil {
+		n, _ = result.RowsAffected()
+	}
+	tracing.EndSQL(span, n, err)
+	return err
+}
+
+// affectedRow turns an Exec that matched no row into models.ErrNotFound.
+func affectedRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+func (m *WebhookRepository) Fetch(ctx context.Context) (res []models.Webhook_subscription, err error) {
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, "SELECT id, url, event_types, active, created_at FROM webhook_subscriptions WHERE tenant_id = $1 ORDER BY id",
+		tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var subs []models.Webhook_subscription
+	for rows.Next() {
+		var sub models.Webhook_subscription
+		if err = rows.Scan(&sub.ID, &sub.Url, pq.Array(&sub.Event_types), &sub.Active, &sub.Created_at); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (m *WebhookRepository) GetByID(ctx context.Context, id int64) (res models.Webhook_subscription, err error) {
+	var sub models.Webhook_subscription
+	row := conn(ctx, m.Conn).QueryRowContext(ctx, "SELECT id, url, event_types, active, created_at FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2",
+		id, tenant.FromContext(ctx).ID)
+	err = row.Scan(&sub.ID, &sub.Url, pq.Array(&sub.Event_types), &sub.Active, &sub.Created_at)
+	if err == sql.ErrNoRows {
+		return sub, models.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+	return sub, nil
+}
+
+func (m *WebhookRepository) Create(ctx context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error) {
+	row := conn(ctx, m.Conn).QueryRowContext(ctx, `INSERT INTO webhook_subscriptions(url, secret, event_types, active, tenant_id)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		sub.Url, sub.Secret, pq.Array(sub.Event_types), sub.Active, tenant.FromContext(ctx).ID)
+	if err := row.Scan(&sub.ID, &sub.Created_at); err != nil {
+		return models.Webhook_subscription{}, err
+	}
+	return sub, nil
+}
+
+func (m *WebhookRepository) Update(ctx context.Context, sub models.Webhook_subscription, id int64) error {
+	return affectedRow(conn(ctx, m.Conn).ExecContext(ctx, "UPDATE webhook_subscriptions SET url = $1, event_types = $2, active = $3 WHERE id = $4 AND tenant_id = $5",
+		sub.Url, pq.Array(sub.Event_types), sub.Active, id, tenant.FromContext(ctx).ID))
+}
+
+func (m *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	return affectedRow(conn(ctx, m.Conn).ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2", id, tenant.FromContext(ctx).ID))
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due at now
+// by pushing their next attempt to leaseUntil, so concurrent dispatchers on
+// other instances skip them while they are in flight. It serves every
+// tenant, as does SaveAttempt.
+func (m *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) (res []models.Webhook_delivery, err error) {
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, `UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING d.id, d.subscription_id, s.url, s.secret, d.event_type, d.payload, d.attempts`,
+		now, leaseUntil, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var deliveries []models.Webhook_delivery
+	for rows.Next() {
+		var d models.Webhook_delivery
+		if err = rows.Scan(&d.ID, &d.Subscription_id, &d.Url, &d.Secret, &d.Event_type, &d.Payload, &d.Attempts); err != nil {
+			return nil, err
+		}
+		d.Status = models.DeliveryPending
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// SaveAttempt records the outcome of a delivery attempt.
+func (m *WebhookRepository) SaveAttempt(ctx context.Context, d models.Webhook_delivery) error {
+	_, err := conn(ctx, m.Conn).ExecContext(ctx, `UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $5`,
+		d.Status, d.Attempts, d.Next_attempt_at, d.Last_error, d.ID)
+	return err
+}
+
+func (m *WebhookRepository) FetchDeadDeliveries(ctx context.Context) (res []models.Webhook_delivery, err error) {
+	rows, err := conn(ctx, m.Conn).QueryContext(ctx, `SELECT d.id, d.subscription_id, s.url, d.event_type, d.payload, d.status,
+		d.attempts, d.next_attempt_at, d.last_error, d.created_at
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'dead' AND d.tenant_id = $1 ORDER BY d.id`, tenant.FromContext(ctx).ID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var deliveries []models.Webhook_delivery
+	for rows.Next() {
+		var d models.Webhook_delivery
+		if err = rows.Scan(&d.ID, &d.Subscription_id, &d.Url, &d.Event_type, &d.Payload, &d.Status,
+			&d.Attempts, &d.Next_attempt_at, &d.Last_error, &d.Created_at); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Requeue moves a dead delivery back to pending with a fresh attempt budget.
+// A delivery that is missing or not dead is models.ErrNotFound.
+func (m *WebhookRepository) Requeue(ctx context.Context, id int64) error {
+	return affectedRow(conn(ctx, m.Conn).ExecContext(ctx, `UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = ''
+		WHERE id = $1 AND status = 'dead' AND tenant_id = $2`, id, tenant.FromContext(ctx).ID))
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestWebhookRepository_Create(t *testing.T) {
+	created := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	sub := models.Webhook_subscription{
+		Url:         "https://example.com/hook",
+		Secret:      "s3cret",
+		Event_types: []string{models.EventTodoCreated},
+		Active:      true,
+	}
+	want := sub
+	want.ID = 7
+	want.Created_at = created
+
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		wantRes     models.Webhook_subscription
+		wantErr     bool
+	}{
+		{
+			name: "success to add subscription",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+					WithArgs(sub.Url, sub.Secret, sqlmock.AnyArg(), sub.Active, tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, created))
+			},
+			wantRes: want,
+			wantErr: false,
+		},
+		{
+			name: "failed to add subscription",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+					WillReturnError(fmt.Errorf("some error"))
+			},
+			wantRes: models.Webhook_subscription{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			m := &WebhookRepository{Conn: db}
+			gotRes, err := m.Create(context.Background(), sub)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WebhookRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("WebhookRepository.Create() = %v, want %v", gotRes, tt.wantRes)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	lease := now.Add(time.Minute)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "subscription_id", "url", "secret", "event_type", "payload", "attempts"}).
+		AddRow(1, 2, "https://example.com/hook", "s3cret", models.EventTodoDeleted, `{"event":"todo.deleted"}`, 3)
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE webhook_deliveries d SET next_attempt_at = $2")).
+		WithArgs(now, lease, 10).
+		WillReturnRows(rows)
+
+	m := &WebhookRepository{Conn: db}
+	got, err := m.ClaimDueDeliveries(context.Background(), now, lease, 10)
+	if err != nil {
+		t.Fatalf("WebhookRepository.ClaimDueDeliveries() error = %v", err)
+	}
+	want := []models.Webhook_delivery{{
+		ID:              1,
+		Subscription_id: 2,
+		Url:             "https://example.com/hook",
+		Secret:          "s3cret",
+		Event_type:      models.EventTodoDeleted,
+		Payload:         `{"event":"todo.deleted"}`,
+		Status:          models.DeliveryPending,
+		Attempts:        3,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WebhookRepository.ClaimDueDeliveries() = %v, want %v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWebhookRepository_SaveAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	d := models.Webhook_delivery{
+		ID:              4,
+		Status:          models.DeliveryDead,
+		Attempts:        8,
+		Next_attempt_at: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Last_error:      "unexpected status 500",
+	}
+	mock.ExpectExec("UPDATE webhook_deliveries").
+		WithArgs(d.Status, d.Attempts, d.Next_attempt_at, d.Last_error, d.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	m := &WebhookRepository{Conn: db}
+	if err := m.SaveAttempt(context.Background(), d); err != nil {
+		t.Errorf("WebhookRepository.SaveAttempt() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWebhookRepository_NotFound(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockClosure func(mock sqlmock.Sqlmock)
+		call        func(m *WebhookRepository) error
+	}{
+		{
+			name: "get",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions").
+					WithArgs(5, tenant.DefaultID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "active", "created_at"}))
+			},
+			call: func(m *WebhookRepository) error {
+				_, err := m.GetByID(context.Background(), 5)
+				return err
+			},
+		},
+		{
+			name: "update",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE webhook_subscriptions").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call: func(m *WebhookRepository) error {
+				return m.Update(context.Background(), models.Webhook_subscription{Url: "https://example.com/hook"}, 5)
+			},
+		},
+		{
+			name: "delete",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM webhook_subscriptions").
+					WithArgs(5, tenant.DefaultID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call: func(m *WebhookRepository) error {
+				return m.Delete(context.Background(), 5)
+			},
+		},
+		{
+			name: "requeue",
+			mockClosure: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE webhook_deliveries").
+					WithArgs(5, tenant.DefaultID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			call: func(m *WebhookRepository) error {
+				return m.Requeue(context.Background(), 5)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.mockClosure(mock)
+
+			if err := tt.call(&WebhookRepository{Conn: db}); !errors.Is(err, models.ErrNotFound) {
+				t.Errorf("WebhookRepository error = %v, want %v", err, models.ErrNotFound)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/config"
+	_handler "github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/handler/graphqlhandler"
+	"github.com/KennyKur/CRUD_Todo/handler/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// registerRoutes mounts the REST API on r. It is shared with the OpenAPI
+// drift test so the spec is checked against the real route table.
+func registerRoutes(r *gin.Engine, cfg *config.Config, todo _handler.TodoUsecaseInterface, webhook _handler.WebhookUsecaseInterface,
+	apiKey _handler.ApiKeyUsecaseInterface, share _handler.ShareUsecaseInterface) error {
+	doc, err := openapi.Load()
+	if err != nil {
+		return err
+	}
+	validator, err := openapi.Validator(doc)
+	if err != nil {
+		return err
+	}
+	if err := openapi.NewDocsHandler(r, doc); err != nil {
+		return err
+	}
+
+	deprecatedAt, sunset, err := cfg.Deprecation.Times()
+	if err != nil {
+		return err
+	}
+	// Only the todo routes have a /v2 successor; webhooks and GraphQL are
+	// served from /v1 alone.
+	deprecation := _handler.Deprecation{At: deprecatedAt, Sunset: sunset, Successor: "/v2/todos"}
+	_handler.NewTodoHandler(r.Group("/v1", _handler.Deprecated(deprecation), validator), todo)
+	api := r.Group("/v1", validator)
+	_handler.NewWebhookHandler(api, webhook)
+	graphqlhandler.NewGraphQLHandler(api, todo, graphqlhandler.Config{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+		BatchWait:     time.Duration(cfg.GraphQL.BatchWaitMs) * time.Millisecond,
+	})
+
+	v2 := r.Group("/v2", validator)
+	_handler.NewTodoHandlerV2(v2, todo)
+	_handler.NewShareHandler(v2, share)
+	_handler.NewApiKeyHandler(v2, apiKey)
+	return nil
+}
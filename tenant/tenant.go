@@ -0,0 +1,97 @@
+// Package tenant carries the tenant a request was resolved to. The
+// delivery layers store it in the context and the repositories scope every
+// query with it.
+package tenant
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// DefaultID is the tenant of rows written before tenancy existed and of
+// requests that name no tenant.
+const DefaultID = "default"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx resolved to t.
+func NewContext(ctx context.Context, t models.Tenant) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext returns the tenant stored in ctx, or the default tenant with
+// the built-in denylist for work that is not tied to a request.
+func FromContext(ctx context.Context) models.Tenant {
+	if ctx != nil {
+		if t, ok := ctx.Value(ctxKey{}).(models.Tenant); ok {
+			return t
+		}
+	}
+	return models.Tenant{ID: DefaultID}
+}
+
+// Config is the tenancy section of the configuration.
+type Config struct {
+	// Header names the request header that selects a tenant.
+	Header string
+	// BaseDomain enables resolution from the subdomain: a request for
+	// acme.<BaseDomain> belongs to tenant acme.
+	BaseDomain string `mapstructure:"base_domain"`
+	Tenants    map[string]models.Tenant
+}
+
+// Registry holds the configured tenants. The default tenant always exists.
+// Set replaces them while requests are being served.
+type Registry struct {
+	tenants atomic.Pointer[map[string]models.Tenant]
+}
+
+func NewRegistry(tenants map[string]models.Tenant) *Registry {
+	r := &Registry{}
+	r.Set(tenants)
+	return r
+}
+
+// Set replaces the tenants. Requests already resolved keep the tenant, and
+// denylist, they started with.
+func (r *Registry) Set(tenants map[string]models.Tenant) {
+	m := map[string]models.Tenant{}
+	for id, t := range tenants {
+		t.ID = strings.ToLower(id)
+		m[t.ID] = t
+	}
+	if _, ok := m[DefaultID]; !ok {
+		m[DefaultID] = models.Tenant{ID: DefaultID}
+	}
+	r.tenants.Store(&m)
+}
+
+// Lookup finds a tenant by id, ignoring case.
+func (r *Registry) Lookup(id string) (models.Tenant, bool) {
+	t, ok := (*r.tenants.Load())[strings.ToLower(id)]
+	return t, ok
+}
+
+func (r *Registry) Default() models.Tenant {
+	return (*r.tenants.Load())[DefaultID]
+}
+
+// FromHost returns the tenant id encoded as the subdomain of host under
+// baseDomain, if any.
+func FromHost(host, baseDomain string) (string, bool) {
+	if baseDomain == "" {
+		return "", false
+	}
+	host = strings.ToLower(host)
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+	sub := strings.TrimSuffix(host, "."+strings.ToLower(baseDomain))
+	if sub == host || sub == "" || strings.Contains(sub, ".") {
+		return "", false
+	}
+	return sub, true
+}
@@ -0,0 +1,67 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got.ID != DefaultID || got.Denylist != nil {
+		t.Errorf("FromContext() without tenant = %+v, want default", got)
+	}
+	if got := FromContext(nil); got.ID != DefaultID {
+		t.Errorf("FromContext(nil) = %+v, want default", got)
+	}
+	acme := models.Tenant{ID: "acme", Denylist: []string{"rapat"}}
+	if got := FromContext(NewContext(context.Background(), acme)); got.ID != "acme" {
+		t.Errorf("FromContext() = %+v, want %+v", got, acme)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(map[string]models.Tenant{"Acme": {Name: "Acme"}})
+	if got, ok := r.Lookup("ACME"); !ok || got.ID != "acme" {
+		t.Errorf("Lookup(ACME) = %+v, %v", got, ok)
+	}
+	if _, ok := r.Lookup("globex"); ok {
+		t.Error("Lookup(globex) found an unconfigured tenant")
+	}
+	if got := r.Default(); got.ID != DefaultID {
+		t.Errorf("Default() = %+v", got)
+	}
+
+	r.Set(map[string]models.Tenant{"globex": {Denylist: []string{"rapat"}}})
+	if _, ok := r.Lookup("acme"); ok {
+		t.Error("Lookup(acme) found a tenant removed by Set()")
+	}
+	if got, ok := r.Lookup("globex"); !ok || len(got.Denylist) != 1 {
+		t.Errorf("Lookup(globex) after Set() = %+v, %v", got, ok)
+	}
+	if got := r.Default(); got.ID != DefaultID {
+		t.Errorf("Default() after Set() = %+v", got)
+	}
+}
+
+func TestFromHost(t *testing.T) {
+	tests := []struct {
+		host   string
+		base   string
+		want   string
+		wantOK bool
+	}{
+		{"acme.todo.example.com", "todo.example.com", "acme", true},
+		{"Acme.Todo.Example.com:8080", "todo.example.com", "acme", true},
+		{"todo.example.com", "todo.example.com", "", false},
+		{"a.b.todo.example.com", "todo.example.com", "", false},
+		{"acme.other.com", "todo.example.com", "", false},
+		{"acme.todo.example.com", "", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := FromHost(tt.host, tt.base)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("FromHost(%q, %q) = %q, %v, want %q, %v", tt.host, tt.base, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
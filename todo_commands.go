@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/client"
+	"github.com/KennyKur/CRUD_Todo/config"
+	_handler "github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/repository"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	"github.com/KennyKur/CRUD_Todo/tui"
+	"github.com/KennyKur/CRUD_Todo/usecase"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// todoService is what the todo commands need, served either by the
+// usecase against the database or by the REST API.
+type todoService interface {
+	Fetch(ctx context.Context) ([]models.User_todo_list, error)
+	GetByID(ctx context.Context, id int64) (models.User_todo_list, error)
+	Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error)
+	Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error)
+	Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// apiTodos adapts the client to todoService and tui.Service, fetching
+// every todo at once.
+type apiTodos struct{ *client.Client }
+
+func (a apiTodos) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	return a.Client.Fetch(ctx)
+}
+
+// target selects where the todo commands go: the REST API at api when it
+// is set, the configured database otherwise.
+type target struct {
+	configFile *string
+	api        string
+	apiKey     string
+	tenant     string
+	user       string
+	output     string
+}
+
+func (t *target) register(cmd *cobra.Command) {
+	fs := cmd.PersistentFlags()
+	fs.StringVar(&t.api, "api", os.Getenv("TODO_API_URL"),
+		"base URL of the REST API, e.g. http://localhost:8080; the database is used when empty (env TODO_API_URL)")
+	fs.StringVar(&t.apiKey, "api-key", os.Getenv("TODO_API_KEY"), "API key for --api (env TODO_API_KEY)")
+	fs.StringVar(&t.tenant, "tenant", tenant.DefaultID, "tenant to work in")
+	fs.StringVar(&t.user, "user", "", "act as this user; database only, with --api the key decides")
+}
+
+// open returns the service and the context to call it with. release closes
+// the database, if one was opened.
+func (t *target) open(ctx context.Context) (svc todoService, _ context.Context, release func(), err error) {
+	if t.api != "" {
+		if t.user != "" {
+			return nil, nil, nil, usageError{errors.New("--user tidak berlaku dengan --api")}
+		}
+		c := client.New(t.api, client.WithAPIKey(t.apiKey), client.WithTenant(t.tenant, ""))
+		return apiTodos{c}, ctx, func() {}, nil
+	}
+	cfg, err := config.NewLoader(*t.configFile).Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tn, ok := tenant.NewRegistry(cfg.Tenancy.Tenants).Lookup(t.tenant)
+	if !ok {
+		return nil, nil, nil, usageError{fmt.Errorf("tenant %q tidak dikenal", t.tenant)}
+	}
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	isolation, err := repository.ParseIsolation(cfg.Database.Isolation)
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, err
+	}
+	svc = usecase.NewTodoUsecase(repository.NewTodoRepository(db, nil), repository.NewShareRepository(db),
+		repository.NewTxManager(db, isolation, cfg.Database.TxMaxAttempts), nil)
+	ctx = tenant.NewContext(ctx, tn)
+	if t.user != "" {
+		ctx = _handler.WithUser(ctx, t.user)
+	}
+	return svc, ctx, func() { db.Close() }, nil
+}
+
+// run opens the target for the duration of fn.
+func (t *target) run(cmd *cobra.Command, fn func(ctx context.Context, svc todoService) error) error {
+	svc, ctx, release, err := t.open(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(ctx, svc)
+}
+
+// completeIDs offers the ids of the todos with their task as description.
+func (t *target) completeIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var ids []string
+	err := t.run(cmd, func(ctx context.Context, svc todoService) error {
+		todos, err := svc.Fetch(ctx)
+		for _, todo := range todos {
+			ids = append(ids, fmt.Sprintf("%d\t%s", todo.ID, todo.Task_name))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newTodoCmd(configFile *string) *cobra.Command {
+	t := &target{configFile: configFile}
+	cmd := &cobra.Command{
+		Use:   "todo",
+		Short: "Manage todos in the database or through the REST API",
+	}
+	t.register(cmd)
+	registerOutput(cmd, &t.output)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the todos",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				todos, err := svc.Fetch(ctx)
+				if err != nil {
+					return err
+				}
+				return printTodos(cmd.OutOrStdout(), t.output, todos)
+			})
+		},
+	}, &cobra.Command{
+		Use:   "add TASK...",
+		Short: "Add a todo; the words of TASK are joined by spaces",
+		Args:  args(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, a []string) error {
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				todo, err := svc.Create(ctx, models.User_todo_list{Task_name: strings.Join(a, " ")})
+				if err != nil {
+					return err
+				}
+				return printTodo(cmd.OutOrStdout(), t.output, todo)
+			})
+		},
+	}, &cobra.Command{
+		Use:   "edit ID TASK...",
+		Short: "Replace the task of a todo",
+		Args:  args(cobra.MinimumNArgs(2)),
+		ValidArgsFunction: func(cmd *cobra.Command, a []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(a) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return t.completeIDs(cmd, a, toComplete)
+		},
+		RunE: func(cmd *cobra.Command, a []string) error {
+			id, err := parseID(a[0])
+			if err != nil {
+				return err
+			}
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				todo, err := svc.Update(ctx, models.User_todo_list{Task_name: strings.Join(a[1:], " ")}, id)
+				if err != nil {
+					return err
+				}
+				return printTodo(cmd.OutOrStdout(), t.output, todo)
+			})
+		},
+	}, &cobra.Command{
+		Use:               "done ID...",
+		Short:             "Mark todos as done and print them",
+		Args:              args(cobra.MinimumNArgs(1)),
+		ValidArgsFunction: t.completeIDs,
+		RunE: func(cmd *cobra.Command, a []string) error {
+			ids, err := parseIDs(a)
+			if err != nil {
+				return err
+			}
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				var done []models.User_todo_list
+				for _, id := range ids {
+					todo, err := svc.Patch(ctx, id, models.User_todo_list{Done: true}, []string{"done"})
+					if err != nil {
+						printTodos(cmd.OutOrStdout(), t.output, done)
+						return fmt.Errorf("todo %d: %w", id, err)
+					}
+					done = append(done, todo)
+				}
+				return printTodos(cmd.OutOrStdout(), t.output, done)
+			})
+		},
+	}, &cobra.Command{
+		Use:               "rm ID...",
+		Short:             "Delete todos",
+		Args:              args(cobra.MinimumNArgs(1)),
+		ValidArgsFunction: t.completeIDs,
+		RunE: func(cmd *cobra.Command, a []string) error {
+			ids, err := parseIDs(a)
+			if err != nil {
+				return err
+			}
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				for _, id := range ids {
+					if err := svc.Delete(ctx, id); err != nil {
+						return fmt.Errorf("todo %d: %w", id, err)
+					}
+				}
+				return nil
+			})
+		},
+	})
+	return cmd
+}
+
+func newImportCmd(configFile *string) *cobra.Command {
+	t := &target{configFile: configFile}
+	cmd := &cobra.Command{
+		Use:   "import [FILE]",
+		Short: "Create the todos in a JSON array, as written by export",
+		Long: "Create the todos in a JSON array, as written by export, from FILE or\n" +
+			"standard input. Only task_name and done are used; ids and owners are\n" +
+			"assigned anew.\n" +
+			"Import stops at the first todo that fails, after printing those created.",
+		Args: args(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, a []string) error {
+			in := cmd.InOrStdin()
+			if len(a) == 1 && a[0] != "-" {
+				f, err := os.Open(a[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+			var todos []models.User_todo_list
+			if err := json.NewDecoder(in).Decode(&todos); err != nil {
+				return fmt.Errorf("membaca todo: %w", err)
+			}
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				created := make([]models.User_todo_list, 0, len(todos))
+				for i, todo := range todos {
+					res, err := svc.Create(ctx, models.User_todo_list{Task_name: todo.Task_name})
+					if err != nil {
+						printTodos(cmd.OutOrStdout(), t.output, created)
+						return fmt.Errorf("todo ke-%d: %w", i+1, err)
+					}
+					if todo.Done {
+						// Create leaves done alone, so it is patched on after.
+						done, err := svc.Patch(ctx, res.ID, models.User_todo_list{Done: true}, []string{"done"})
+						if err != nil {
+							printTodos(cmd.OutOrStdout(), t.output, append(created, res))
+							return fmt.Errorf("todo ke-%d: %w", i+1, err)
+						}
+						res = done
+					}
+					created = append(created, res)
+				}
+				return printTodos(cmd.OutOrStdout(), t.output, created)
+			})
+		},
+	}
+	t.register(cmd)
+	registerOutput(cmd, &t.output)
+	return cmd
+}
+
+func newExportCmd(configFile *string) *cobra.Command {
+	t := &target{configFile: configFile}
+	var file string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the todos as a JSON array",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return t.run(cmd, func(ctx context.Context, svc todoService) error {
+				todos, err := svc.Fetch(ctx)
+				if err != nil {
+					return err
+				}
+				if todos == nil {
+					todos = []models.User_todo_list{}
+				}
+				out := cmd.OutOrStdout()
+				if file != "" && file != "-" {
+					f, err := os.Create(file)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					out = f
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(todos)
+			})
+		},
+	}
+	t.register(cmd)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write to this file instead of standard output")
+	return cmd
+}
+
+func newTUICmd() *cobra.Command {
+	var (
+		api, apiKey, tenantID string
+		refresh, timeout      time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Manage todos interactively through the REST API",
+		Args:  args(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c := client.New(api, client.WithAPIKey(apiKey), client.WithTenant(tenantID, ""))
+			m := tui.New(cmd.Context(), apiTodos{c}, refresh, timeout)
+			_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(cmd.Context()),
+				tea.WithInput(cmd.InOrStdin()), tea.WithOutput(cmd.OutOrStdout())).Run()
+			return err
+		},
+	}
+	api = os.Getenv("TODO_API_URL")
+	if api == "" {
+		api = "http://localhost:8080"
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&api, "api", api, "base URL of the REST API (env TODO_API_URL)")
+	fs.StringVar(&apiKey, "api-key", os.Getenv("TODO_API_KEY"), "API key (env TODO_API_KEY)")
+	fs.StringVar(&tenantID, "tenant", tenant.DefaultID, "tenant to work in")
+	fs.DurationVar(&refresh, "refresh", 5*time.Second, "reload the todos this often; never when 0")
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "give up on a request after this long")
+	return cmd
+}
+
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 1 {
+		return 0, usageError{fmt.Errorf("id %q tidak valid", s)}
+	}
+	return id, nil
+}
+
+func parseIDs(a []string) ([]int64, error) {
+	ids := make([]int64, len(a))
+	for i, s := range a {
+		id, err := parseID(s)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func printTodo(w io.Writer, format string, todo models.User_todo_list) error {
+	if format == outputJSON {
+		return writeJSON(w, todo)
+	}
+	return printTodos(w, format, []models.User_todo_list{todo})
+}
+
+func printTodos(w io.Writer, format string, todos []models.User_todo_list) error {
+	if format == outputJSON {
+		if todos == nil {
+			todos = []models.User_todo_list{}
+		}
+		return writeJSON(w, todos)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTASK\tDONE\tOWNER")
+	for _, todo := range todos {
+		fmt.Fprintf(tw, "%d\t%s\t%t\t%s\n", todo.ID, todo.Task_name, todo.Done, todo.Owner)
+	}
+	return tw.Flush()
+}
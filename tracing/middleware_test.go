@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := recordSpans(t)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	r := gin.New()
+	r.Use(Middleware())
+	var inner trace.SpanContext
+	r.GET("/v2/todos/:id", func(c *gin.Context) {
+		inner = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/v2/todos/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /v2/todos/:id" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want the one from traceparent", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span id = %s", got)
+	}
+	if inner.SpanID() != span.SpanContext().SpanID() {
+		t.Error("handler context does not carry the request span")
+	}
+	want := attribute.Int("http.response.status_code", http.StatusOK)
+	found := false
+	for _, kv := range span.Attributes() {
+		found = found || kv == want
+	}
+	if !found {
+		t.Errorf("attributes = %v, want %v", span.Attributes(), want)
+	}
+}
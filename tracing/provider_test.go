@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordSpans installs a provider that keeps finished spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return rec
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Config{Exporter: "file", File: file, ServiceName: "crud-todo", SampleRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "hello")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"Name":"hello"`) || !strings.Contains(string(b), "crud-todo") {
+		t.Errorf("trace file = %s", b)
+	}
+}
+
+func TestSetupZeroSampleRatio(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Config{Exporter: "file", File: file, SampleRatio: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "hello")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(file); err != nil || len(b) != 0 {
+		t.Errorf("trace file = %q, %v, want it empty", b, err)
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "zipkin"}); err == nil {
+		t.Error("Setup() error = nil, want unknown exporter error")
+	}
+}
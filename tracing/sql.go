@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	sqlStrings = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumbers = regexp.MustCompile(`\$?\b\d+(?:\.\d+)?\b`)
+	sqlSpaces  = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL replaces string and numeric literals with ? so statement text
+// can be recorded without leaking data. Bind placeholders such as $1 are
+// kept.
+func SanitizeSQL(query string) string {
+	query = sqlStrings.ReplaceAllString(query, "?")
+	query = sqlNumbers.ReplaceAllStringFunc(query, func(s string) string {
+		if s[0] == '$' {
+			return s
+		}
+		return "?"
+	})
+	return sqlSpaces.ReplaceAllString(query, " ")
+}
+
+// StartSQL starts a client span for one statement. name should be the
+// operation and table, e.g. "SELECT user_todo_lists". A nil ctx starts a new
+// trace.
+func StartSQL(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(instrumentation).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", SanitizeSQL(query)),
+		))
+}
+
+// EndSQL records the number of rows returned or affected and err, then ends
+// the span.
+func EndSQL(span trace.Span, rows int64, err error) {
+	span.SetAttributes(attribute.Int64("db.response.rows", rows))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,19 @@
+package tracing
+
+import "testing"
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT id FROM user_todo_lists WHERE id = $1", "SELECT id FROM user_todo_lists WHERE id = $1"},
+		{"SELECT id FROM t WHERE name = 'tidur' AND id = 42", "SELECT id FROM t WHERE name = ? AND id = ?"},
+		{"UPDATE t SET a = 'it''s'\n\t WHERE b = 1.5", "UPDATE t SET a = ? WHERE b = ?"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeSQL(tt.query); got != tt.want {
+			t.Errorf("SanitizeSQL(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
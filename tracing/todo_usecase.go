@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type todoUsecase struct {
+	next   handler.TodoUsecaseInterface
+	tracer trace.Tracer
+}
+
+// NewTodoUsecase wraps next so every call runs in a "TodoUsecase.<Method>"
+// span. Domain errors are recorded on the span but do not mark it failed.
+func NewTodoUsecase(next handler.TodoUsecaseInterface) handler.TodoUsecaseInterface {
+	return &todoUsecase{next: next, tracer: otel.Tracer(instrumentation)}
+}
+
+func (u *todoUsecase) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return u.tracer.Start(ctx, "TodoUsecase."+method, trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrInvalidTask) && !errors.Is(err, models.ErrForbidden) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+func (u *todoUsecase) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "Fetch")
+	res, err := u.next.Fetch(ctx)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	ctx, span := u.start(ctx, "FetchPage", attribute.Int("page.limit", limit), attribute.Int("page.offset", offset))
+	res, err := u.next.FetchPage(ctx, filter, limit, offset)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "GetByID", attribute.Int64("todo.id", id))
+	res, err := u.next.GetByID(ctx, id)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "FetchByIDs", attribute.Int("todo.count", len(ids)))
+	res, err := u.next.FetchByIDs(ctx, ids)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "Create")
+	res, err := u.next.Create(ctx, todo)
+	span.SetAttributes(attribute.Int64("todo.id", res.ID))
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "Update", attribute.Int64("todo.id", id))
+	res, err := u.next.Update(ctx, todo, id)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "Patch", attribute.Int64("todo.id", id), attribute.StringSlice("todo.fields", fields))
+	res, err := u.next.Patch(ctx, id, todo, fields)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) Merge(ctx context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	ctx, span := u.start(ctx, "Merge", attribute.Int64("todo.id", id))
+	res, err := u.next.Merge(ctx, id, apply)
+	end(span, err)
+	return res, err
+}
+
+func (u *todoUsecase) Delete(ctx context.Context, id int64) error {
+	ctx, span := u.start(ctx, "Delete", attribute.Int64("todo.id", id))
+	err := u.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+// Watch is long-lived, so only the subscription is traced.
+func (u *todoUsecase) Watch(ctx context.Context) <-chan models.Todo_event {
+	_, span := u.start(ctx, "Watch")
+	defer span.End()
+	return u.next.Watch(ctx)
+}
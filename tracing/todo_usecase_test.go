@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTodoUsecase(t *testing.T) {
+	rec := recordSpans(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUC := NewMockTodoUsecaseInterface(ctrl)
+	u := NewTodoUsecase(mockUC)
+
+	mockUC.EXPECT().GetByID(gomock.Any(), int64(1)).DoAndReturn(func(ctx context.Context, id int64) (models.User_todo_list, error) {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			t.Error("usecase called without the span in its context")
+		}
+		return models.User_todo_list{}, models.ErrNotFound
+	})
+	mockUC.EXPECT().Delete(gomock.Any(), int64(2)).Return(errors.New("connection refused"))
+
+	u.GetByID(context.Background(), 1)
+	u.Delete(context.Background(), 2)
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	if spans[0].Name() != "TodoUsecase.GetByID" || spans[0].Status().Code == codes.Error {
+		t.Errorf("GetByID span = %s %v, want not found recorded without error status", spans[0].Name(), spans[0].Status())
+	}
+	if spans[1].Name() != "TodoUsecase.Delete" || spans[1].Status().Code != codes.Error {
+		t.Errorf("Delete span = %s %v, want error status", spans[1].Name(), spans[1].Status())
+	}
+}
@@ -0,0 +1,442 @@
+// Package tui is an interactive terminal client for the todo REST API.
+//
+// Completing a todo patches its done field on the server; completing it
+// again reopens it.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Service is the part of the API the TUI uses.
+type Service interface {
+	Fetch(ctx context.Context) ([]models.User_todo_list, error)
+	Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error)
+	Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error)
+	Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type mode int
+
+const (
+	browsing mode = iota
+	adding
+	editing
+	filtering
+	confirming
+)
+
+// help lists the keys of each mode, shown at the bottom.
+var help = map[mode]string{
+	browsing:   "a add · e edit · d delete · space done · / filter · r refresh · q quit",
+	adding:     "enter save · esc cancel",
+	editing:    "enter save · esc cancel",
+	filtering:  "enter keep · esc clear",
+	confirming: "y delete · any other key cancel",
+}
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	faintStyle  = lipgloss.NewStyle().Faint(true)
+	doneStyle   = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	cursorStyle = lipgloss.NewStyle().Bold(true)
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+type (
+	tickMsg    struct{}
+	fetchedMsg struct {
+		todos []models.User_todo_list
+		err   error
+	}
+	// savedMsg ends an add, edit, toggle or delete.
+	savedMsg struct{ err error }
+)
+
+// Model is the tea.Model of the TUI.
+type Model struct {
+	ctx     context.Context
+	svc     Service
+	refresh time.Duration
+	timeout time.Duration
+	now     func() time.Time
+
+	todos  []models.User_todo_list
+	filter string
+	cursor int
+	// offset is the first row shown when the list is taller than the
+	// terminal.
+	offset int
+	mode   mode
+	input  textinput.Model
+	// editID is the todo being edited or deleted.
+	editID int64
+
+	loaded  bool
+	synced  time.Time
+	err     error
+	offline bool
+	width   int
+	height  int
+}
+
+// New returns a model reading todos from svc, again every refresh unless
+// it is 0. Each request is abandoned after timeout.
+func New(ctx context.Context, svc Service, refresh, timeout time.Duration) Model {
+	input := textinput.New()
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return Model{
+		ctx:     ctx,
+		svc:     svc,
+		refresh: refresh,
+		timeout: timeout,
+		now:     time.Now,
+		input:   input,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.fetch(), m.tick())
+}
+
+func (m Model) tick() tea.Cmd {
+	if m.refresh <= 0 {
+		return nil
+	}
+	return tea.Tick(m.refresh, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+// call runs fn as a command, within the request timeout.
+func (m Model) call(fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	ctx, timeout := m.ctx, m.timeout
+	return func() tea.Msg {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return fn(ctx)
+	}
+}
+
+func (m Model) fetch() tea.Cmd {
+	svc := m.svc
+	return m.call(func(ctx context.Context) tea.Msg {
+		todos, err := svc.Fetch(ctx)
+		return fetchedMsg{todos, err}
+	})
+}
+
+// items returns the rows shown: the todos on the server narrowed by the
+// filter.
+func (m Model) items() []models.User_todo_list {
+	filter := strings.ToLower(m.filter)
+	var items []models.User_todo_list
+	for _, todo := range m.todos {
+		if strings.Contains(strings.ToLower(todo.Task_name), filter) {
+			items = append(items, todo)
+		}
+	}
+	return items
+}
+
+func (m Model) selected() (models.User_todo_list, bool) {
+	items := m.items()
+	if m.cursor < 0 || m.cursor >= len(items) {
+		return models.User_todo_list{}, false
+	}
+	return items[m.cursor], true
+}
+
+// rows is how many todos fit on the screen, or 0 when the height is not
+// known yet.
+func (m Model) rows() int {
+	if m.height == 0 {
+		return 0
+	}
+	// title, status line and help, plus the input when there is one
+	rows := m.height - 3
+	if m.mode == adding || m.mode == editing || m.mode == filtering {
+		rows--
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// clamp keeps the cursor on a row and the row on the screen.
+func (m *Model) clamp() {
+	n := len(m.items())
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	rows := m.rows()
+	if rows == 0 {
+		m.offset = 0
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+}
+
+func (m *Model) fail(err error) {
+	m.err = err
+	var urlErr *url.Error
+	m.offline = errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tickMsg:
+		cmd = tea.Batch(m.fetch(), m.tick())
+	case fetchedMsg:
+		if msg.err != nil {
+			m.fail(msg.err)
+			break
+		}
+		m.todos, m.loaded, m.synced = msg.todos, true, m.now()
+		m.err, m.offline = nil, false
+	case savedMsg:
+		if msg.err != nil {
+			m.fail(msg.err)
+			break
+		}
+		cmd = m.fetch()
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case browsing:
+			return m.browse(msg)
+		case confirming:
+			return m.confirm(msg)
+		default:
+			return m.edit(msg)
+		}
+	}
+	m.clamp()
+	return m, cmd
+}
+
+func (m Model) browse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	sel, ok := m.selected()
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.items()) - 1
+	case "pgup":
+		m.cursor -= m.rows()
+	case "pgdown":
+		m.cursor += m.rows()
+	case "r":
+		cmd = m.fetch()
+	case "a":
+		m.mode = adding
+		m.input.Reset()
+		m.input.Placeholder = "task baru"
+		cmd = m.input.Focus()
+	case "e", "enter":
+		if ok {
+			m.mode, m.editID = editing, sel.ID
+			m.input.SetValue(sel.Task_name)
+			m.input.CursorEnd()
+			cmd = m.input.Focus()
+		}
+	case "d", "delete":
+		if ok {
+			m.mode, m.editID = confirming, sel.ID
+		}
+	case " ", "x":
+		if ok {
+			cmd = m.toggle(sel)
+		}
+	case "/":
+		m.mode = filtering
+		m.input.SetValue(m.filter)
+		m.input.Placeholder = "filter"
+		m.input.CursorEnd()
+		cmd = m.input.Focus()
+	case "esc":
+		m.filter = ""
+	}
+	m.clamp()
+	return m, cmd
+}
+
+// toggle completes an open todo and reopens a completed one.
+func (m Model) toggle(sel models.User_todo_list) tea.Cmd {
+	svc := m.svc
+	return m.call(func(ctx context.Context) tea.Msg {
+		_, err := svc.Patch(ctx, sel.ID, models.User_todo_list{Done: !sel.Done}, []string{"done"})
+		return savedMsg{err}
+	})
+}
+
+func (m Model) confirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = browsing
+	if msg.String() != "y" {
+		return m, nil
+	}
+	sel, ok := m.selected()
+	if !ok || sel.ID != m.editID {
+		return m, nil
+	}
+	svc, id := m.svc, sel.ID
+	return m, m.call(func(ctx context.Context) tea.Msg {
+		return savedMsg{svc.Delete(ctx, id)}
+	})
+}
+
+// edit handles the keys of the modes that type into the input.
+func (m Model) edit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.mode == filtering {
+			m.filter = ""
+		}
+		m.mode = browsing
+		m.input.Blur()
+		m.clamp()
+		return m, nil
+	case tea.KeyEnter:
+		task := strings.TrimSpace(m.input.Value())
+		md, id, svc := m.mode, m.editID, m.svc
+		m.mode = browsing
+		m.input.Blur()
+		m.clamp()
+		if task == "" || md == filtering {
+			return m, nil
+		}
+		return m, m.call(func(ctx context.Context) tea.Msg {
+			var err error
+			if md == adding {
+				_, err = svc.Create(ctx, models.User_todo_list{Task_name: task})
+			} else {
+				_, err = svc.Update(ctx, models.User_todo_list{Task_name: task}, id)
+			}
+			return savedMsg{err}
+		})
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.mode == filtering {
+		m.filter = m.input.Value()
+		m.cursor = 0
+	}
+	m.clamp()
+	return m, cmd
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	title := titleStyle.Render("Todos")
+	done := 0
+	for _, todo := range m.todos {
+		if todo.Done {
+			done++
+		}
+	}
+	title += faintStyle.Render(fmt.Sprintf("  %d open · %d done", len(m.todos)-done, done))
+	if m.filter != "" {
+		title += faintStyle.Render(fmt.Sprintf(" · filter %q", m.filter))
+	}
+	b.WriteString(m.line(title))
+
+	items := m.items()
+	end := len(items)
+	if rows := m.rows(); rows > 0 && m.offset+rows < end {
+		end = m.offset + rows
+	}
+	switch {
+	case !m.loaded && m.err == nil:
+		b.WriteString(m.line(faintStyle.Render("memuat…")))
+	case m.loaded && len(items) == 0:
+		b.WriteString(m.line(faintStyle.Render("tidak ada todo")))
+	}
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.line(m.row(items[i], i == m.cursor)))
+	}
+
+	switch m.mode {
+	case adding, editing, filtering:
+		b.WriteString(m.line(m.input.View()))
+	}
+	b.WriteString(m.line(m.status()))
+	b.WriteString(faintStyle.Render(help[m.mode]))
+	return b.String()
+}
+
+func (m Model) row(it models.User_todo_list, current bool) string {
+	mark, box, task := "  ", "[ ]", it.Task_name
+	if it.Done {
+		box, task = "[x]", doneStyle.Render(task)
+	}
+	if current {
+		mark = cursorStyle.Render("> ")
+	}
+	row := mark + box + " " + task
+	if it.Owner != "" {
+		row += faintStyle.Render("  " + it.Owner)
+	}
+	return row
+}
+
+func (m Model) status() string {
+	switch {
+	case m.mode == confirming:
+		if sel, ok := m.selected(); ok {
+			return fmt.Sprintf("hapus %q?", sel.Task_name)
+		}
+	case m.offline:
+		msg := "server tidak dapat dihubungi: " + m.err.Error()
+		if m.refresh > 0 {
+			msg += fmt.Sprintf(" · mencoba lagi tiap %s", m.refresh)
+		}
+		return errorStyle.Render(msg)
+	case m.err != nil:
+		return errorStyle.Render(m.err.Error())
+	case m.loaded:
+		return faintStyle.Render("diperbarui " + m.synced.Format("15:04:05"))
+	}
+	return ""
+}
+
+// line ends s with a newline, cut to the terminal width.
+func (m Model) line(s string) string {
+	if m.width > 0 {
+		s = lipgloss.NewStyle().MaxWidth(m.width).Render(s)
+	}
+	return s + "\n"
+}
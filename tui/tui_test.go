@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeService struct {
+	todos  map[int64]string
+	done   map[int64]bool
+	nextID int64
+	err    error
+}
+
+func newFake(tasks ...string) *fakeService {
+	f := &fakeService{todos: map[int64]string{}, done: map[int64]bool{}}
+	for _, task := range tasks {
+		f.Create(context.Background(), models.User_todo_list{Task_name: task})
+	}
+	return f
+}
+
+func (f *fakeService) Fetch(ctx context.Context) ([]models.User_todo_list, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var res []models.User_todo_list
+	for id, task := range f.todos {
+		res = append(res, models.User_todo_list{ID: id, Task_name: task, Done: f.done[id]})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (f *fakeService) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	if f.err != nil {
+		return models.User_todo_list{}, f.err
+	}
+	f.nextID++
+	f.todos[f.nextID] = todo.Task_name
+	todo.ID = f.nextID
+	return todo, nil
+}
+
+func (f *fakeService) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	if f.err != nil {
+		return models.User_todo_list{}, f.err
+	}
+	if _, ok := f.todos[id]; !ok {
+		return models.User_todo_list{}, models.ErrNotFound
+	}
+	f.todos[id] = todo.Task_name
+	todo.ID = id
+	return todo, nil
+}
+
+func (f *fakeService) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	if f.err != nil {
+		return models.User_todo_list{}, f.err
+	}
+	if _, ok := f.todos[id]; !ok {
+		return models.User_todo_list{}, models.ErrNotFound
+	}
+	for _, field := range fields {
+		switch field {
+		case "task_name":
+			f.todos[id] = todo.Task_name
+		case "done":
+			f.done[id] = todo.Done
+		}
+	}
+	return models.User_todo_list{ID: id, Task_name: f.todos[id], Done: f.done[id]}, nil
+}
+
+func (f *fakeService) Delete(ctx context.Context, id int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.todos[id]; !ok {
+		return models.ErrNotFound
+	}
+	delete(f.todos, id)
+	delete(f.done, id)
+	return nil
+}
+
+// send delivers msg and then, synchronously, whatever the commands it
+// starts produce.
+func send(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	return run(m, cmd)
+}
+
+func run(m tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = run(m, c)
+		}
+	case fetchedMsg, savedMsg:
+		m = send(m, msg)
+	}
+	return m
+}
+
+// keys types each rune of s, or the named key for strings like "enter".
+func keys(m tea.Model, ks ...string) tea.Model {
+	named := map[string]tea.KeyType{
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "up": tea.KeyUp, "down": tea.KeyDown, "space": tea.KeySpace,
+	}
+	for _, k := range ks {
+		if t, ok := named[k]; ok {
+			m = send(m, tea.KeyMsg{Type: t})
+			continue
+		}
+		for _, r := range k {
+			m = send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	return m
+}
+
+func start(svc Service) tea.Model {
+	m := New(context.Background(), svc, 0, 0)
+	return run(m, m.Init())
+}
+
+func tasks(m tea.Model) []string {
+	var res []string
+	for _, it := range m.(Model).items() {
+		task := it.Task_name
+		if it.Done {
+			task += " (done)"
+		}
+		res = append(res, task)
+	}
+	return res
+}
+
+func TestAddEditDelete(t *testing.T) {
+	svc := newFake("beli susu")
+	m := start(svc)
+
+	m = keys(m, "a", "cuci mobil", "enter")
+	if got := strings.Join(tasks(m), ", "); got != "beli susu, cuci mobil" {
+		t.Fatalf("after add: %s", got)
+	}
+
+	m = keys(m, "down", "e", "!", "enter")
+	if svc.todos[2] != "cuci mobil!" {
+		t.Errorf("edit stored %q", svc.todos[2])
+	}
+
+	m = keys(m, "d", "n")
+	if len(svc.todos) != 2 {
+		t.Fatal("delete went ahead without confirmation")
+	}
+	m = keys(m, "d", "y")
+	if got := strings.Join(tasks(m), ", "); got != "beli susu" {
+		t.Errorf("after delete: %s", got)
+	}
+	if m.(Model).cursor != 0 {
+		t.Errorf("cursor = %d, want it back on the last row", m.(Model).cursor)
+	}
+
+	m = keys(m, "a", "esc")
+	if len(svc.todos) != 1 || m.(Model).mode != browsing {
+		t.Errorf("esc should cancel the add")
+	}
+}
+
+func TestToggleCompletion(t *testing.T) {
+	svc := newFake("beli susu", "cuci mobil")
+	m := start(svc)
+
+	m = keys(m, "space")
+	if got := strings.Join(tasks(m), ", "); got != "beli susu (done), cuci mobil" {
+		t.Fatalf("after completing: %s", got)
+	}
+	if svc.todos[1] != "beli susu" || !svc.done[1] {
+		t.Errorf("completed todo should be kept and marked done on the server, got %v %v", svc.todos, svc.done)
+	}
+	if view := m.View(); !strings.Contains(view, "1 open · 1 done") {
+		t.Errorf("counts not shown:\n%s", view)
+	}
+
+	m = keys(m, "space")
+	if got := strings.Join(tasks(m), ", "); got != "beli susu, cuci mobil" {
+		t.Errorf("after reopening: %s", got)
+	}
+	if svc.done[1] || len(svc.todos) != 2 {
+		t.Errorf("reopened todo should keep its id and be open, got %v %v", svc.todos, svc.done)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	m := start(newFake("beli susu", "cuci mobil", "beli roti"))
+
+	m = keys(m, "/", "BELI")
+	if got := strings.Join(tasks(m), ", "); got != "beli susu, beli roti" {
+		t.Errorf("filtered: %s", got)
+	}
+	m = keys(m, "enter", "down")
+	if sel, _ := m.(Model).selected(); sel.Task_name != "beli roti" {
+		t.Errorf("selected %q in the filtered list", sel.Task_name)
+	}
+	m = keys(m, "esc")
+	if len(tasks(m)) != 3 {
+		t.Errorf("esc should clear the filter, got %v", tasks(m))
+	}
+}
+
+func TestOffline(t *testing.T) {
+	svc := newFake("beli susu")
+	m := start(svc)
+
+	svc.err = &url.Error{Op: "Get", URL: "http://localhost:8080/v2/todos", Err: errors.New("connection refused")}
+	m = send(m, tickMsg{})
+	view := m.View()
+	if !m.(Model).offline || !strings.Contains(view, "server tidak dapat dihubungi") {
+		t.Errorf("offline not shown:\n%s", view)
+	}
+	if !strings.Contains(view, "beli susu") {
+		t.Errorf("last todos should stay listed while offline:\n%s", view)
+	}
+
+	svc.err = nil
+	m = keys(m, "r")
+	if m.(Model).offline || strings.Contains(m.View(), "server tidak dapat dihubungi") {
+		t.Error("offline still shown after a successful refresh")
+	}
+
+	m = keys(m, "e")
+	svc.todos = map[int64]string{}
+	m = keys(m, "enter")
+	if m.(Model).offline || !strings.Contains(m.View(), models.ErrNotFound.Error()) {
+		t.Errorf("server errors should be shown as such:\n%s", m.View())
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// apiKeyPrefix starts every key so leaked keys are easy to recognise. A key
+// reads ctd_<prefix>_<secret>; the prefix is stored in clear for lookup and
+// the whole key only as a SHA-256 hash.
+const apiKeyPrefix = "ctd_"
+
+// lastUsedGranularity bounds how often a busy key writes last_used_at.
+const lastUsedGranularity = time.Minute
+
+type ApiKeyUsecase struct {
+	apiKeyRepo ApiKeyRepositoryInterface
+	now        func() time.Time
+}
+
+func NewApiKeyUsecase(a ApiKeyRepositoryInterface) handler.ApiKeyUsecaseInterface {
+	return &ApiKeyUsecase{
+		apiKeyRepo: a,
+		now:        time.Now,
+	}
+}
+
+func validateApiKey(key models.Api_key, now time.Time) error {
+	if strings.TrimSpace(key.Name) == "" {
+		return errors.New("nama tidak valid")
+	}
+	if len(key.Scopes) == 0 {
+		return errors.New("scope tidak valid")
+	}
+	for _, s := range key.Scopes {
+		valid := false
+		for _, known := range models.Scopes {
+			if s == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return errors.New("scope tidak valid")
+		}
+	}
+	if key.Expires_at != nil && !key.Expires_at.After(now) {
+		return errors.New("masa berlaku tidak valid")
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashApiKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitApiKey returns the lookup prefix of a raw key.
+func splitApiKey(raw string) (string, bool) {
+	rest := strings.TrimPrefix(raw, apiKeyPrefix)
+	if rest == raw {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+func (a *ApiKeyUsecase) Fetch(c context.Context) ([]models.Api_key, error) {
+	return a.apiKeyRepo.Fetch(c)
+}
+
+// Create stores a new key. The returned key carries the plaintext in Key;
+// it cannot be recovered afterwards.
+func (a *ApiKeyUsecase) Create(c context.Context, key models.Api_key) (models.Api_key, error) {
+	if err := validateApiKey(key, a.now()); err != nil {
+		return models.Api_key{}, err
+	}
+	prefix, err := randomHex(4)
+	if err != nil {
+		return models.Api_key{}, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return models.Api_key{}, err
+	}
+	raw := apiKeyPrefix + prefix + "_" + secret
+
+	key.ID = 0
+	key.Prefix = prefix
+	key.Hash = hashApiKey(raw)
+	key.Last_used_at = nil
+	key.Revoked_at = nil
+	res, err := a.apiKeyRepo.Create(c, key)
+	if err != nil {
+		return models.Api_key{}, err
+	}
+	res.Key = raw
+	return res, nil
+}
+
+func (a *ApiKeyUsecase) Revoke(c context.Context, id int64) error {
+	return a.apiKeyRepo.Revoke(c, id, a.now())
+}
+
+// Authenticate resolves a raw key. Unknown, revoked and expired keys all
+// return models.ErrUnauthorized so callers cannot tell them apart.
+func (a *ApiKeyUsecase) Authenticate(c context.Context, raw string) (models.Api_key, error) {
+	prefix, ok := splitApiKey(raw)
+	if !ok {
+		return models.Api_key{}, models.ErrUnauthorized
+	}
+	key, err := a.apiKeyRepo.GetByPrefix(c, prefix)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.Api_key{}, models.ErrUnauthorized
+	}
+	if err != nil {
+		return models.Api_key{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashApiKey(raw)), []byte(key.Hash)) != 1 {
+		return models.Api_key{}, models.ErrUnauthorized
+	}
+	now := a.now()
+	if key.Revoked_at != nil || (key.Expires_at != nil && !key.Expires_at.After(now)) {
+		return models.Api_key{}, models.ErrUnauthorized
+	}
+	if key.Last_used_at == nil || now.Sub(*key.Last_used_at) >= lastUsedGranularity {
+		// a failed touch must not fail the request it is recording
+		if err := a.apiKeyRepo.TouchLastUsed(c, key.ID, now); err == nil {
+			key.Last_used_at = &now
+		}
+	}
+	return key, nil
+}
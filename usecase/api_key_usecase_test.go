@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestApiKeyUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockApiKeyRepositoryInterface(ctrl)
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		key     models.Api_key
+		mockFN  func()
+		wantErr bool
+	}{
+		{
+			name: "success to add key",
+			key:  models.Api_key{Name: "ci", Scopes: []string{models.ScopeTodosRead}},
+			mockFN: func() {
+				mockRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key models.Api_key) (models.Api_key, error) {
+						if len(key.Prefix) != 8 || len(key.Hash) != 64 || key.Key != "" {
+							t.Errorf("expected prefix and hash only, got %+v", key)
+						}
+						key.ID = 1
+						return key, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing name",
+			key:     models.Api_key{Scopes: []string{models.ScopeTodosRead}},
+			mockFN:  func() {},
+			wantErr: true,
+		},
+		{
+			name:    "unknown scope",
+			key:     models.Api_key{Name: "ci", Scopes: []string{"todos:admin"}},
+			mockFN:  func() {},
+			wantErr: true,
+		},
+		{
+			name:    "already expired",
+			key:     models.Api_key{Name: "ci", Scopes: []string{models.ScopeTodosRead}, Expires_at: &past},
+			mockFN:  func() {},
+			wantErr: true,
+		},
+		{
+			name: "failed to add key",
+			key:  models.Api_key{Name: "ci", Scopes: []string{models.ScopeTodosRead}},
+			mockFN: func() {
+				mockRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(models.Api_key{}, errors.New("gagal menyimpan data"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &ApiKeyUsecase{
+				apiKeyRepo: mockRepo,
+				now:        func() time.Time { return now },
+			}
+			got, err := a.Create(context.Background(), tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApiKeyUsecase.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			prefix, ok := splitApiKey(got.Key)
+			if !ok || prefix != got.Prefix || hashApiKey(got.Key) != got.Hash {
+				t.Errorf("ApiKeyUsecase.Create() key %q does not match prefix %q and hash", got.Key, got.Prefix)
+			}
+		})
+	}
+}
+
+func TestApiKeyUsecase_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockApiKeyRepositoryInterface(ctrl)
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+	past := now.Add(-time.Hour)
+
+	raw := "ctd_0123abcd_" + strings.Repeat("a", 48)
+	stored := models.Api_key{ID: 5, Prefix: "0123abcd", Hash: hashApiKey(raw), Scopes: []string{models.ScopeTodosRead}}
+	with := func(f func(k *models.Api_key)) models.Api_key {
+		k := stored
+		f(&k)
+		return k
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		mockFN  func()
+		wantErr error
+	}{
+		{
+			name: "valid key",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(stored, nil)
+				mockRepo.EXPECT().TouchLastUsed(gomock.Any(), int64(5), now).Return(nil)
+			},
+		},
+		{
+			name: "recently used key is not touched",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(with(func(k *models.Api_key) { k.Last_used_at = &recent }), nil)
+			},
+		},
+		{
+			name: "failed touch still authenticates",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(stored, nil)
+				mockRepo.EXPECT().TouchLastUsed(gomock.Any(), int64(5), now).Return(errors.New("connection refused"))
+			},
+		},
+		{
+			name:    "malformed key",
+			raw:     "0123abcd",
+			mockFN:  func() {},
+			wantErr: models.ErrUnauthorized,
+		},
+		{
+			name: "unknown prefix",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(models.Api_key{}, models.ErrNotFound)
+			},
+			wantErr: models.ErrUnauthorized,
+		},
+		{
+			name: "wrong secret",
+			raw:  "ctd_0123abcd_wrong",
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(stored, nil)
+			},
+			wantErr: models.ErrUnauthorized,
+		},
+		{
+			name: "revoked key",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(with(func(k *models.Api_key) { k.Revoked_at = &past }), nil)
+			},
+			wantErr: models.ErrUnauthorized,
+		},
+		{
+			name: "expired key",
+			raw:  raw,
+			mockFN: func() {
+				mockRepo.EXPECT().GetByPrefix(gomock.Any(), "0123abcd").Return(with(func(k *models.Api_key) { k.Expires_at = &past }), nil)
+			},
+			wantErr: models.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &ApiKeyUsecase{
+				apiKeyRepo: mockRepo,
+				now:        func() time.Time { return now },
+			}
+			got, err := a.Authenticate(context.Background(), tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ApiKeyUsecase.Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != stored.ID {
+				t.Errorf("ApiKeyUsecase.Authenticate() = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+// authorize checks that the user of c holds at least role need on every
+// todo in ids and returns the user's role on each. Callers that do not act
+// for a user, such as service API keys and anonymous requests on
+// deployments without auth.required, are not subject to roles; for them
+// roles is nil.
+func authorize(c context.Context, shares ShareRepositoryInterface, need string, ids ...int64) (roles map[int64]string, err error) {
+	user, ok := handler.UserFromContext(c)
+	if !ok {
+		return nil, nil
+	}
+	roles, err = shares.Roles(c, user, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		role, found := roles[id]
+		if !found {
+			// not telling apart missing todos and todos of others
+			return nil, models.ErrNotFound
+		}
+		if !models.RoleAtLeast(role, need) {
+			return nil, models.ErrForbidden
+		}
+	}
+	return roles, nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestTodoUsecase_Policy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodos := NewMockTodoRepositoryInterface(ctrl)
+	mockShares := NewMockShareRepositoryInterface(ctrl)
+	a := &TodoUsecase{todoRepo: mockTodos, shareRepo: mockShares}
+	ctx := handler.WithUser(context.Background(), "siti")
+	todo := models.User_todo_list{Task_name: "Belajar"}
+	roles := func(id int64, role string) {
+		mockShares.EXPECT().Roles(gomock.Any(), "siti", []int64{id}).Return(map[int64]string{id: role}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		mockFN  func()
+		wantErr error
+	}{
+		{
+			name: "viewer reads",
+			call: func() error {
+				got, err := a.GetByID(ctx, 1)
+				if err == nil && got.Role != models.RoleViewer {
+					t.Errorf("GetByID() role = %q, want %q", got.Role, models.RoleViewer)
+				}
+				return err
+			},
+			mockFN: func() {
+				roles(1, models.RoleViewer)
+				mockTodos.EXPECT().GetByID(gomock.Any(), int64(1)).Return(models.User_todo_list{ID: 1}, nil)
+			},
+		},
+		{
+			name: "failed read carries no role",
+			call: func() error {
+				got, err := a.GetByID(ctx, 1)
+				if got != (models.User_todo_list{}) {
+					t.Errorf("GetByID() = %v, want the zero todo", got)
+				}
+				return err
+			},
+			mockFN: func() {
+				roles(1, models.RoleViewer)
+				mockTodos.EXPECT().GetByID(gomock.Any(), int64(1)).Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "stranger gets not found",
+			call: func() error {
+				_, err := a.GetByID(ctx, 2)
+				return err
+			},
+			mockFN: func() {
+				mockShares.EXPECT().Roles(gomock.Any(), "siti", []int64{2}).Return(map[int64]string{}, nil)
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "viewer cannot update",
+			call: func() error {
+				_, err := a.Update(ctx, todo, 1)
+				return err
+			},
+			mockFN: func() {
+				roles(1, models.RoleViewer)
+			},
+			wantErr: models.ErrForbidden,
+		},
+		{
+			name: "editor updates",
+			call: func() error {
+				res, err := a.Update(ctx, todo, 1)
+				if err == nil && res.Role != models.RoleEditor {
+					return fmt.Errorf("role = %q, want %q", res.Role, models.RoleEditor)
+				}
+				return err
+			},
+			mockFN: func() {
+				roles(1, models.RoleEditor)
+				mockTodos.EXPECT().Update(gomock.Any(), todo, int64(1)).Return(models.User_todo_list{ID: 1}, nil)
+			},
+		},
+		{
+			name: "editor cannot delete",
+			call: func() error { return a.Delete(ctx, 1) },
+			mockFN: func() {
+				roles(1, models.RoleEditor)
+			},
+			wantErr: models.ErrForbidden,
+		},
+		{
+			name: "owner deletes",
+			call: func() error { return a.Delete(ctx, 1) },
+			mockFN: func() {
+				roles(1, models.RoleOwner)
+				mockTodos.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			},
+		},
+		{
+			name: "create is owned by the caller",
+			call: func() error {
+				got, err := a.Create(ctx, models.User_todo_list{Task_name: "Belajar", Owner: "budi", Role: models.RoleOwner})
+				if err == nil && (got.Owner != "siti" || got.Role != models.RoleOwner) {
+					t.Errorf("Create() = %+v, want owned by siti", got)
+				}
+				return err
+			},
+			mockFN: func() {
+				mockTodos.EXPECT().Create(gomock.Any(), models.User_todo_list{Task_name: "Belajar", Owner: "siti"}).
+					Return(models.User_todo_list{ID: 4, Task_name: "Belajar", Owner: "siti"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_FetchShared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodos := NewMockTodoRepositoryInterface(ctrl)
+	mockShares := NewMockShareRepositoryInterface(ctrl)
+	a := &TodoUsecase{todoRepo: mockTodos, shareRepo: mockShares}
+	ctx := handler.WithUser(context.Background(), "siti")
+
+	accessible := []models.User_todo_list{
+		{ID: 1, Task_name: "Belajar", Owner: "siti", Role: models.RoleOwner},
+		{ID: 3, Task_name: "daily", Owner: "budi", Role: models.RoleViewer},
+	}
+	mockShares.EXPECT().FetchAccessible(gomock.Any(), "siti").Return(accessible, nil)
+	got, err := a.Fetch(ctx)
+	if err != nil || !reflect.DeepEqual(got, accessible) {
+		t.Errorf("Fetch() = %v, %v, want %v", got, err, accessible)
+	}
+
+	page := models.Todo_page{Todos: accessible[1:], Total: 2}
+	mockShares.EXPECT().FetchAccessiblePage(gomock.Any(), "siti", models.Todo_filter{}, 1, 1).Return(page, nil)
+	if got, err := a.FetchPage(ctx, models.Todo_filter{}, 1, 1); err != nil || !reflect.DeepEqual(got, page) {
+		t.Errorf("FetchPage() = %v, %v, want %v", got, err, page)
+	}
+
+	mockShares.EXPECT().Roles(gomock.Any(), "siti", []int64{1, 2, 3}).
+		Return(map[int64]string{1: models.RoleOwner, 3: models.RoleViewer}, nil)
+	mockTodos.EXPECT().FetchByIDs(gomock.Any(), []int64{1, 3}).
+		Return([]models.User_todo_list{{ID: 1, Task_name: "Belajar"}, {ID: 3, Task_name: "daily"}}, nil)
+	got, err = a.FetchByIDs(ctx, []int64{1, 2, 3})
+	if err != nil || len(got) != 2 || got[0].Role != models.RoleOwner || got[1].Role != models.RoleViewer {
+		t.Errorf("FetchByIDs() = %v, %v", got, err)
+	}
+}
@@ -2,14 +2,60 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/KennyKur/CRUD_Todo/models"
 )
 
 type TodoRepositoryInterface interface {
 	Fetch(ctx context.Context) (res []models.User_todo_list, err error)
+	FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error)
 	GetByID(ctx context.Context, id int64) (models.User_todo_list, error)
-	Create(ctx context.Context, todo models.User_todo_list) error
-	Update(ctx context.Context, todo models.User_todo_list, id int64) error
+	GetForUpdate(ctx context.Context, id int64) (models.User_todo_list, error)
+	FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error)
+	Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error)
+	Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error)
+	Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error)
 	Delete(ctx context.Context, id int64) error
 }
+
+type WebhookRepositoryInterface interface {
+	Fetch(ctx context.Context) ([]models.Webhook_subscription, error)
+	GetByID(ctx context.Context, id int64) (models.Webhook_subscription, error)
+	Create(ctx context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error)
+	Update(ctx context.Context, sub models.Webhook_subscription, id int64) error
+	Delete(ctx context.Context, id int64) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.Webhook_delivery, error)
+	SaveAttempt(ctx context.Context, delivery models.Webhook_delivery) error
+	FetchDeadDeliveries(ctx context.Context) ([]models.Webhook_delivery, error)
+	Requeue(ctx context.Context, id int64) error
+}
+
+type ApiKeyRepositoryInterface interface {
+	Fetch(ctx context.Context) ([]models.Api_key, error)
+	GetByPrefix(ctx context.Context, prefix string) (models.Api_key, error)
+	Create(ctx context.Context, key models.Api_key) (models.Api_key, error)
+	Revoke(ctx context.Context, id int64, at time.Time) error
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+type ShareRepositoryInterface interface {
+	FetchAccessible(ctx context.Context, user string) ([]models.User_todo_list, error)
+	FetchAccessiblePage(ctx context.Context, user string, filter models.Todo_filter, limit, offset int) (models.Todo_page, error)
+	Roles(ctx context.Context, user string, ids []int64) (map[int64]string, error)
+	Fetch(ctx context.Context, todoID int64) ([]models.Todo_share, error)
+	Create(ctx context.Context, shares []models.Todo_share) ([]models.Todo_share, error)
+	Delete(ctx context.Context, todoID int64, user string) error
+}
+
+// Transactor runs fn as one unit of work: repository calls made with the
+// context passed to fn commit or roll back together.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Quota reserves one unit of a daily allowance per key. refund gives the
+// unit back when the work it was reserved for fails.
+type Quota interface {
+	Reserve(ctx context.Context, key string) (refund func(), err error)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: usecase/interface_repository.go
+// Source: usecase/repository_interface.go
 
 // Package usecase is a generated GoMock package.
 package usecase
@@ -7,6 +7,7 @@ package usecase
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/KennyKur/CRUD_Todo/models"
 	gomock "github.com/golang/mock/gomock"
@@ -36,11 +37,12 @@ func (m *MockTodoRepositoryInterface) EXPECT() *MockTodoRepositoryInterfaceMockR
 }
 
 // Create mocks base method.
-func (m *MockTodoRepositoryInterface) Create(ctx context.Context, todo models.User_todo_list) error {
+func (m *MockTodoRepositoryInterface) Create(ctx context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, todo)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
@@ -78,6 +80,36 @@ func (mr *MockTodoRepositoryInterfaceMockRecorder) Fetch(ctx interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).Fetch), ctx)
 }
 
+// FetchByIDs mocks base method.
+func (m *MockTodoRepositoryInterface) FetchByIDs(ctx context.Context, ids []int64) ([]models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchByIDs indicates an expected call of FetchByIDs.
+func (mr *MockTodoRepositoryInterfaceMockRecorder) FetchByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByIDs", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).FetchByIDs), ctx, ids)
+}
+
+// FetchPage mocks base method.
+func (m *MockTodoRepositoryInterface) FetchPage(ctx context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPage", ctx, filter, limit, offset)
+	ret0, _ := ret[0].(models.Todo_page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPage indicates an expected call of FetchPage.
+func (mr *MockTodoRepositoryInterfaceMockRecorder) FetchPage(ctx, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPage", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).FetchPage), ctx, filter, limit, offset)
+}
+
 // GetByID mocks base method.
 func (m *MockTodoRepositoryInterface) GetByID(ctx context.Context, id int64) (models.User_todo_list, error) {
 	m.ctrl.T.Helper()
@@ -93,12 +125,43 @@ func (mr *MockTodoRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).GetByID), ctx, id)
 }
 
+// GetForUpdate mocks base method.
+func (m *MockTodoRepositoryInterface) GetForUpdate(ctx context.Context, id int64) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockTodoRepositoryInterfaceMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).GetForUpdate), ctx, id)
+}
+
+// Patch mocks base method.
+func (m *MockTodoRepositoryInterface) Patch(ctx context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, todo, fields)
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockTodoRepositoryInterfaceMockRecorder) Patch(ctx, id, todo, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).Patch), ctx, id, todo, fields)
+}
+
 // Update mocks base method.
-func (m *MockTodoRepositoryInterface) Update(ctx context.Context, todo models.User_todo_list, id int64) error {
+func (m *MockTodoRepositoryInterface) Update(ctx context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Update", ctx, todo, id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
@@ -106,3 +169,440 @@ func (mr *MockTodoRepositoryInterfaceMockRecorder) Update(ctx, todo, id interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoRepositoryInterface)(nil).Update), ctx, todo, id)
 }
+
+// MockWebhookRepositoryInterface is a mock of WebhookRepositoryInterface interface.
+type MockWebhookRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryInterfaceMockRecorder
+}
+
+// MockWebhookRepositoryInterfaceMockRecorder is the mock recorder for MockWebhookRepositoryInterface.
+type MockWebhookRepositoryInterfaceMockRecorder struct {
+	mock *MockWebhookRepositoryInterface
+}
+
+// NewMockWebhookRepositoryInterface creates a new mock instance.
+func NewMockWebhookRepositoryInterface(ctrl *gomock.Controller) *MockWebhookRepositoryInterface {
+	mock := &MockWebhookRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepositoryInterface) EXPECT() *MockWebhookRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockWebhookRepositoryInterface) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Webhook_delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, now, leaseUntil, limit)
+	ret0, _ := ret[0].([]models.Webhook_delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) ClaimDueDeliveries(ctx, now, leaseUntil, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).ClaimDueDeliveries), ctx, now, leaseUntil, limit)
+}
+
+// Create mocks base method.
+func (m *MockWebhookRepositoryInterface) Create(ctx context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, sub)
+	ret0, _ := ret[0].(models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) Create(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).Create), ctx, sub)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookRepositoryInterface) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).Delete), ctx, id)
+}
+
+// Fetch mocks base method.
+func (m *MockWebhookRepositoryInterface) Fetch(ctx context.Context) ([]models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].([]models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) Fetch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).Fetch), ctx)
+}
+
+// FetchDeadDeliveries mocks base method.
+func (m *MockWebhookRepositoryInterface) FetchDeadDeliveries(ctx context.Context) ([]models.Webhook_delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchDeadDeliveries", ctx)
+	ret0, _ := ret[0].([]models.Webhook_delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchDeadDeliveries indicates an expected call of FetchDeadDeliveries.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) FetchDeadDeliveries(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchDeadDeliveries", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).FetchDeadDeliveries), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockWebhookRepositoryInterface) GetByID(ctx context.Context, id int64) (models.Webhook_subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(models.Webhook_subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// Requeue mocks base method.
+func (m *MockWebhookRepositoryInterface) Requeue(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) Requeue(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).Requeue), ctx, id)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockWebhookRepositoryInterface) SaveAttempt(ctx context.Context, delivery models.Webhook_delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) SaveAttempt(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).SaveAttempt), ctx, delivery)
+}
+
+// Update mocks base method.
+func (m *MockWebhookRepositoryInterface) Update(ctx context.Context, sub models.Webhook_subscription, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, sub, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookRepositoryInterfaceMockRecorder) Update(ctx, sub, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookRepositoryInterface)(nil).Update), ctx, sub, id)
+}
+
+// MockApiKeyRepositoryInterface is a mock of ApiKeyRepositoryInterface interface.
+type MockApiKeyRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyRepositoryInterfaceMockRecorder
+}
+
+// MockApiKeyRepositoryInterfaceMockRecorder is the mock recorder for MockApiKeyRepositoryInterface.
+type MockApiKeyRepositoryInterfaceMockRecorder struct {
+	mock *MockApiKeyRepositoryInterface
+}
+
+// NewMockApiKeyRepositoryInterface creates a new mock instance.
+func NewMockApiKeyRepositoryInterface(ctrl *gomock.Controller) *MockApiKeyRepositoryInterface {
+	mock := &MockApiKeyRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockApiKeyRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyRepositoryInterface) EXPECT() *MockApiKeyRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockApiKeyRepositoryInterface) Create(ctx context.Context, key models.Api_key) (models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockApiKeyRepositoryInterfaceMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApiKeyRepositoryInterface)(nil).Create), ctx, key)
+}
+
+// Fetch mocks base method.
+func (m *MockApiKeyRepositoryInterface) Fetch(ctx context.Context) ([]models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].([]models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockApiKeyRepositoryInterfaceMockRecorder) Fetch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockApiKeyRepositoryInterface)(nil).Fetch), ctx)
+}
+
+// GetByPrefix mocks base method.
+func (m *MockApiKeyRepositoryInterface) GetByPrefix(ctx context.Context, prefix string) (models.Api_key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(models.Api_key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPrefix indicates an expected call of GetByPrefix.
+func (mr *MockApiKeyRepositoryInterfaceMockRecorder) GetByPrefix(ctx, prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrefix", reflect.TypeOf((*MockApiKeyRepositoryInterface)(nil).GetByPrefix), ctx, prefix)
+}
+
+// Revoke mocks base method.
+func (m *MockApiKeyRepositoryInterface) Revoke(ctx context.Context, id int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockApiKeyRepositoryInterfaceMockRecorder) Revoke(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockApiKeyRepositoryInterface)(nil).Revoke), ctx, id, at)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockApiKeyRepositoryInterface) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockApiKeyRepositoryInterfaceMockRecorder) TouchLastUsed(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockApiKeyRepositoryInterface)(nil).TouchLastUsed), ctx, id, at)
+}
+
+// MockShareRepositoryInterface is a mock of ShareRepositoryInterface interface.
+type MockShareRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepositoryInterfaceMockRecorder
+}
+
+// MockShareRepositoryInterfaceMockRecorder is the mock recorder for MockShareRepositoryInterface.
+type MockShareRepositoryInterfaceMockRecorder struct {
+	mock *MockShareRepositoryInterface
+}
+
+// NewMockShareRepositoryInterface creates a new mock instance.
+func NewMockShareRepositoryInterface(ctrl *gomock.Controller) *MockShareRepositoryInterface {
+	mock := &MockShareRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepositoryInterface) EXPECT() *MockShareRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockShareRepositoryInterface) Create(ctx context.Context, shares []models.Todo_share) ([]models.Todo_share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, shares)
+	ret0, _ := ret[0].([]models.Todo_share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockShareRepositoryInterfaceMockRecorder) Create(ctx, shares interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShareRepositoryInterface)(nil).Create), ctx, shares)
+}
+
+// Delete mocks base method.
+func (m *MockShareRepositoryInterface) Delete(ctx context.Context, todoID int64, user string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, todoID, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockShareRepositoryInterfaceMockRecorder) Delete(ctx, todoID, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockShareRepositoryInterface)(nil).Delete), ctx, todoID, user)
+}
+
+// Fetch mocks base method.
+func (m *MockShareRepositoryInterface) Fetch(ctx context.Context, todoID int64) ([]models.Todo_share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, todoID)
+	ret0, _ := ret[0].([]models.Todo_share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockShareRepositoryInterfaceMockRecorder) Fetch(ctx, todoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockShareRepositoryInterface)(nil).Fetch), ctx, todoID)
+}
+
+// FetchAccessible mocks base method.
+func (m *MockShareRepositoryInterface) FetchAccessible(ctx context.Context, user string) ([]models.User_todo_list, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAccessible", ctx, user)
+	ret0, _ := ret[0].([]models.User_todo_list)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAccessible indicates an expected call of FetchAccessible.
+func (mr *MockShareRepositoryInterfaceMockRecorder) FetchAccessible(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAccessible", reflect.TypeOf((*MockShareRepositoryInterface)(nil).FetchAccessible), ctx, user)
+}
+
+// FetchAccessiblePage mocks base method.
+func (m *MockShareRepositoryInterface) FetchAccessiblePage(ctx context.Context, user string, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAccessiblePage", ctx, user, filter, limit, offset)
+	ret0, _ := ret[0].(models.Todo_page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAccessiblePage indicates an expected call of FetchAccessiblePage.
+func (mr *MockShareRepositoryInterfaceMockRecorder) FetchAccessiblePage(ctx, user, filter, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAccessiblePage", reflect.TypeOf((*MockShareRepositoryInterface)(nil).FetchAccessiblePage), ctx, user, filter, limit, offset)
+}
+
+// Roles mocks base method.
+func (m *MockShareRepositoryInterface) Roles(ctx context.Context, user string, ids []int64) (map[int64]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", ctx, user, ids)
+	ret0, _ := ret[0].(map[int64]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *MockShareRepositoryInterfaceMockRecorder) Roles(ctx, user, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*MockShareRepositoryInterface)(nil).Roles), ctx, user, ids)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTransactorMockRecorder) WithinTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockQuota is a mock of Quota interface.
+type MockQuota struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaMockRecorder
+}
+
+// MockQuotaMockRecorder is the mock recorder for MockQuota.
+type MockQuotaMockRecorder struct {
+	mock *MockQuota
+}
+
+// NewMockQuota creates a new mock instance.
+func NewMockQuota(ctrl *gomock.Controller) *MockQuota {
+	mock := &MockQuota{ctrl: ctrl}
+	mock.recorder = &MockQuotaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuota) EXPECT() *MockQuotaMockRecorder {
+	return m.recorder
+}
+
+// Reserve mocks base method.
+func (m *MockQuota) Reserve(ctx context.Context, key string) (func(), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockQuotaMockRecorder) Reserve(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockQuota)(nil).Reserve), ctx, key)
+}
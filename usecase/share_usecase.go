@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+type ShareUsecase struct {
+	shareRepo ShareRepositoryInterface
+	tx        Transactor
+}
+
+func NewShareUsecase(s ShareRepositoryInterface, tx Transactor) handler.ShareUsecaseInterface {
+	return &ShareUsecase{
+		shareRepo: s,
+		tx:        tx,
+	}
+}
+
+// Fetch lists whom a todo is shared with. Anyone who can view the todo may
+// see its shares.
+func (a *ShareUsecase) Fetch(c context.Context, todoID int64) ([]models.Todo_share, error) {
+	if _, err := authorize(c, a.shareRepo, models.RoleViewer, todoID); err != nil {
+		return nil, err
+	}
+	return a.shareRepo.Fetch(c, todoID)
+}
+
+// Share grants user role on each of todoIDs, all or none. Only owners may
+// share a todo.
+func (a *ShareUsecase) Share(c context.Context, todoIDs []int64, user string, role string) ([]models.Todo_share, error) {
+	user = strings.TrimSpace(user)
+	if caller, _ := handler.UserFromContext(c); user == "" || user == caller {
+		return nil, fmt.Errorf("%w: user tidak valid", models.ErrInvalidShare)
+	}
+	if len(todoIDs) == 0 {
+		return nil, fmt.Errorf("%w: todo_ids kosong", models.ErrInvalidShare)
+	}
+	if !models.IsRole(role) {
+		return nil, fmt.Errorf("%w: role tidak valid", models.ErrInvalidShare)
+	}
+	shares := make([]models.Todo_share, 0, len(todoIDs))
+	seen := map[int64]bool{}
+	for _, id := range todoIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		shares = append(shares, models.Todo_share{Todo_id: id, User: user, Role: role})
+	}
+	var res []models.Todo_share
+	err := withinTx(c, a.tx, func(c context.Context) (err error) {
+		if _, err = authorize(c, a.shareRepo, models.RoleOwner, todoIDs...); err != nil {
+			return err
+		}
+		res, err = a.shareRepo.Create(c, shares)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Revoke removes the share of user on a todo. Owners may revoke anyone;
+// other users may only give up their own share.
+func (a *ShareUsecase) Revoke(c context.Context, todoID int64, user string) error {
+	need := models.RoleOwner
+	if caller, ok := handler.UserFromContext(c); ok && caller == user {
+		need = models.RoleViewer
+	}
+	return withinTx(c, a.tx, func(c context.Context) error {
+		if _, err := authorize(c, a.shareRepo, need, todoID); err != nil {
+			return err
+		}
+		return a.shareRepo.Delete(c, todoID, user)
+	})
+}
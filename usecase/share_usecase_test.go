@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestShareUsecase_Share(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShareRepositoryInterface(ctrl)
+	ctx := handler.WithUser(context.Background(), "budi")
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		user    string
+		role    string
+		mockFN  func()
+		wantErr error
+	}{
+		{
+			name: "owner shares a group",
+			ids:  []int64{1, 2, 1},
+			user: "siti",
+			role: models.RoleEditor,
+			mockFN: func() {
+				mockRepo.EXPECT().Roles(gomock.Any(), "budi", []int64{1, 2, 1}).
+					Return(map[int64]string{1: models.RoleOwner, 2: models.RoleOwner}, nil)
+				mockRepo.EXPECT().Create(gomock.Any(), []models.Todo_share{
+					{Todo_id: 1, User: "siti", Role: models.RoleEditor},
+					{Todo_id: 2, User: "siti", Role: models.RoleEditor},
+				}).Return(nil, nil)
+			},
+		},
+		{
+			name: "editor cannot share",
+			ids:  []int64{1, 2},
+			user: "siti",
+			role: models.RoleViewer,
+			mockFN: func() {
+				mockRepo.EXPECT().Roles(gomock.Any(), "budi", []int64{1, 2}).
+					Return(map[int64]string{1: models.RoleOwner, 2: models.RoleEditor}, nil)
+			},
+			wantErr: models.ErrForbidden,
+		},
+		{
+			name: "todo of someone else",
+			ids:  []int64{3},
+			user: "siti",
+			role: models.RoleViewer,
+			mockFN: func() {
+				mockRepo.EXPECT().Roles(gomock.Any(), "budi", []int64{3}).Return(map[int64]string{}, nil)
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:    "unknown role",
+			ids:     []int64{1},
+			user:    "siti",
+			role:    "admin",
+			mockFN:  func() {},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "sharing with yourself",
+			ids:     []int64{1},
+			user:    "budi",
+			role:    models.RoleViewer,
+			mockFN:  func() {},
+			wantErr: models.ErrInvalidShare,
+		},
+		{
+			name:    "no todos",
+			user:    "siti",
+			role:    models.RoleViewer,
+			mockFN:  func() {},
+			wantErr: models.ErrInvalidShare,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &ShareUsecase{
+				shareRepo: mockRepo,
+			}
+			if _, err := a.Share(ctx, tt.ids, tt.user, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ShareUsecase.Share() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShareUsecase_Revoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShareRepositoryInterface(ctrl)
+	ctx := handler.WithUser(context.Background(), "siti")
+
+	tests := []struct {
+		name    string
+		user    string
+		mockFN  func()
+		wantErr error
+	}{
+		{
+			name: "viewer leaves",
+			user: "siti",
+			mockFN: func() {
+				mockRepo.EXPECT().Roles(gomock.Any(), "siti", []int64{1}).Return(map[int64]string{1: models.RoleViewer}, nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), int64(1), "siti").Return(nil)
+			},
+		},
+		{
+			name: "viewer cannot revoke others",
+			user: "andi",
+			mockFN: func() {
+				mockRepo.EXPECT().Roles(gomock.Any(), "siti", []int64{1}).Return(map[int64]string{1: models.RoleViewer}, nil)
+			},
+			wantErr: models.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &ShareUsecase{
+				shareRepo: mockRepo,
+			}
+			if err := a.Revoke(ctx, 1, tt.user); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ShareUsecase.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+)
+
+// TodoBroker fans todo events out to in-process watchers of the same
+// tenant. Events are only seen by watchers connected to the instance that
+// handled the mutation.
+type TodoBroker struct {
+	mu   sync.Mutex
+	subs map[chan models.Todo_event]string
+}
+
+func NewTodoBroker() *TodoBroker {
+	return &TodoBroker{subs: make(map[chan models.Todo_event]string)}
+}
+
+// Subscribe returns a channel receiving the events of the tenant of ctx
+// until ctx is done. Slow watchers drop events rather than blocking writers.
+func (b *TodoBroker) Subscribe(ctx context.Context) <-chan models.Todo_event {
+	ch := make(chan models.Todo_event, 16)
+	b.mu.Lock()
+	b.subs[ch] = tenant.FromContext(ctx).ID
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch
+}
+
+func (b *TodoBroker) Publish(tenantID string, ev models.Todo_event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, t := range b.subs {
+		if t != tenantID {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestTodoUsecase_Watch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockTodoRepositoryInterface(ctrl)
+	a := &TodoUsecase{todoRepo: mockRepo, broker: NewTodoBroker()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := a.Watch(ctx)
+
+	todo := models.User_todo_list{Task_name: "Belajar"}
+	mockRepo.EXPECT().Create(gomock.Any(), todo).Return(models.User_todo_list{ID: 3, Task_name: "Belajar"}, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), todo, int64(3)).Return(models.User_todo_list{ID: 3, Task_name: "Belajar"}, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+	if _, err := a.Create(ctx, todo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Update(ctx, todo, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		event string
+		id    int64
+	}{
+		{models.EventTodoCreated, 3},
+		{models.EventTodoUpdated, 3},
+		{models.EventTodoDeleted, 3},
+	}
+	for _, w := range want {
+		select {
+		case ev := <-events:
+			if ev.Event != w.event || ev.Data.ID != w.id {
+				t.Errorf("event = %s/%d, want %s/%d", ev.Event, ev.Data.ID, w.event, w.id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", w.event)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected channel to be closed after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Error("channel not closed after cancel")
+	}
+}
+
+func TestTodoBroker_TenantIsolation(t *testing.T) {
+	b := NewTodoBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	acme := b.Subscribe(tenant.NewContext(ctx, models.Tenant{ID: "acme"}))
+	def := b.Subscribe(ctx)
+
+	b.Publish("acme", models.Todo_event{Event: models.EventTodoCreated, Data: models.User_todo_list{ID: 1}})
+	select {
+	case ev := <-acme:
+		if ev.Data.ID != 1 {
+			t.Errorf("acme event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("acme watcher did not receive its event")
+	}
+	select {
+	case ev := <-def:
+		t.Errorf("default tenant received an acme event: %+v", ev)
+	default:
+	}
+}
@@ -2,22 +2,56 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/logging"
 	"github.com/KennyKur/CRUD_Todo/models"
+	"github.com/KennyKur/CRUD_Todo/tenant"
 )
 
+// TodoUsecase checks the caller's role before every read and write: viewer
+// to read, editor to change and owner to delete a todo. See authorize for
+// callers that are not users. The check and the write it guards run as one
+// unit of work.
 type TodoUsecase struct {
-	todoRepo TodoRepositoryInterface
+	todoRepo  TodoRepositoryInterface
+	shareRepo ShareRepositoryInterface
+	tx        Transactor
+	broker    *TodoBroker
+	quota     Quota
 }
 
-func NewTodoUsecase(a TodoRepositoryInterface) handler.TodoUsecaseInterface {
+// NewTodoUsecase builds the usecase. quota, when not nil, caps the todos
+// each user of a tenant creates per day.
+func NewTodoUsecase(a TodoRepositoryInterface, s ShareRepositoryInterface, tx Transactor, quota Quota) handler.TodoUsecaseInterface {
 	return &TodoUsecase{
-		todoRepo: a,
+		todoRepo:  a,
+		shareRepo: s,
+		tx:        tx,
+		broker:    NewTodoBroker(),
+		quota:     quota,
 	}
 }
 
+func (a *TodoUsecase) publish(c context.Context, event string, todo models.User_todo_list) {
+	logging.FromContext(c).Debug("todo event", "event", event, "todo_id", todo.ID)
+	if a.broker == nil {
+		return
+	}
+	a.broker.Publish(tenant.FromContext(c).ID, models.Todo_event{
+		Event:       event,
+		Occurred_at: time.Now().UTC(),
+		Data:        todo,
+	})
+}
+
+// Fetch returns the todos the caller owns or has been shared, each with
+// the caller's role.
 func (a *TodoUsecase) Fetch(c context.Context) (res []models.User_todo_list, err error) {
+	if user, ok := handler.UserFromContext(c); ok {
+		return a.shareRepo.FetchAccessible(c, user)
+	}
 	res, err = a.todoRepo.Fetch(c)
 	if err != nil {
 		return nil, err
@@ -26,33 +60,226 @@ func (a *TodoUsecase) Fetch(c context.Context) (res []models.User_todo_list, err
 
 }
 
-func (a *TodoUsecase) GetByID(c context.Context, id int64) (res models.User_todo_list, err error) {
-	res, err = a.todoRepo.GetByID(c, id)
-	return
+// FetchPage returns limit todos matching filter from offset on, ordered by
+// id, or every one from offset on when limit is 0. With a user only the
+// todos they can access are counted.
+func (a *TodoUsecase) FetchPage(c context.Context, filter models.Todo_filter, limit, offset int) (models.Todo_page, error) {
+	if user, ok := handler.UserFromContext(c); ok {
+		return a.shareRepo.FetchAccessiblePage(c, user, filter, limit, offset)
+	}
+	return a.todoRepo.FetchPage(c, filter, limit, offset)
 }
 
-func (a *TodoUsecase) Create(c context.Context, todo models.User_todo_list) error {
-	err := a.todoRepo.Create(c, todo)
+func (a *TodoUsecase) GetByID(c context.Context, id int64) (models.User_todo_list, error) {
+	roles, err := authorize(c, a.shareRepo, models.RoleViewer, id)
 	if err != nil {
-		return err
+		return models.User_todo_list{}, err
+	}
+	res, err := a.todoRepo.GetByID(c, id)
+	if err != nil {
+		return models.User_todo_list{}, err
 	}
+	res.Role = roles[id]
+	return res, nil
+}
 
-	return nil
+// FetchByIDs loads several todos in one round trip; ids that do not exist
+// or that the caller cannot view are omitted from the result.
+func (a *TodoUsecase) FetchByIDs(c context.Context, ids []int64) ([]models.User_todo_list, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	user, ok := handler.UserFromContext(c)
+	if !ok {
+		return a.todoRepo.FetchByIDs(c, ids)
+	}
+	roles, err := a.shareRepo.Roles(c, user, ids)
+	if err != nil {
+		return nil, err
+	}
+	var visible []int64
+	for _, id := range ids {
+		if _, ok := roles[id]; ok {
+			visible = append(visible, id)
+		}
+	}
+	if len(visible) == 0 {
+		return nil, nil
+	}
+	todos, err := a.todoRepo.FetchByIDs(c, visible)
+	if err != nil {
+		return nil, err
+	}
+	for i := range todos {
+		todos[i].Role = roles[todos[i].ID]
+	}
+	return todos, nil
+}
+
+// Create stores todo owned by the caller, or by nobody when the caller is
+// not a user. Each user has a daily quota; other callers are counted by
+// their API key or address.
+func (a *TodoUsecase) Create(c context.Context, todo models.User_todo_list) (models.User_todo_list, error) {
+	todo.Owner, _ = handler.UserFromContext(c)
+	todo.Role = ""
+	refund := func() {}
+	if a.quota != nil {
+		var err error
+		refund, err = a.quota.Reserve(c, quotaKey(c, todo.Owner))
+		if err != nil {
+			return models.User_todo_list{}, err
+		}
+	}
+	res, err := a.todoRepo.Create(c, todo)
+	if err != nil {
+		refund()
+		return models.User_todo_list{}, err
+	}
+	a.publish(c, models.EventTodoCreated, res)
+	if res.Owner != "" {
+		res.Role = models.RoleOwner
+	}
+	return res, nil
+}
+
+// quotaKey is who a create counts against within the tenant: the owning
+// user, else the client from handler.WithClient.
+func quotaKey(c context.Context, owner string) string {
+	who := "user:" + owner
+	if owner == "" {
+		who, _ = handler.ClientFromContext(c)
+	}
+	return tenant.FromContext(c).ID + "|" + who
 }
 
-func (a *TodoUsecase) Update(c context.Context, todo models.User_todo_list, id int64) error {
-	err := a.todoRepo.Update(c, todo, id)
+// Update overwrites the todo and returns it as stored.
+func (a *TodoUsecase) Update(c context.Context, todo models.User_todo_list, id int64) (models.User_todo_list, error) {
+	var (
+		roles map[int64]string
+		res   models.User_todo_list
+	)
+	err := withinTx(c, a.tx, func(c context.Context) (err error) {
+		if roles, err = authorize(c, a.shareRepo, models.RoleEditor, id); err != nil {
+			return err
+		}
+		res, err = a.todoRepo.Update(c, todo, id)
+		return err
+	})
 	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	a.publish(c, models.EventTodoUpdated, res)
+	res.Role = roles[id]
+	return res, nil
+}
+
+// Patch writes only the named fields of todo. An empty field list is a no-op
+// that returns the stored todo.
+func (a *TodoUsecase) Patch(c context.Context, id int64, todo models.User_todo_list, fields []string) (models.User_todo_list, error) {
+	var (
+		roles map[int64]string
+		res   models.User_todo_list
+	)
+	err := withinTx(c, a.tx, func(c context.Context) (err error) {
+		if roles, err = authorize(c, a.shareRepo, models.RoleEditor, id); err != nil {
+			return err
+		}
+		res, err = a.todoRepo.Patch(c, id, todo, fields)
 		return err
+	})
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	if len(fields) > 0 {
+		a.publish(c, models.EventTodoUpdated, res)
 	}
+	res.Role = roles[id]
+	return res, nil
+}
 
-	return nil
+// Merge reads the todo from the primary inside the write transaction and
+// writes the fields apply reports as changed. apply may run again when the
+// transaction is retried.
+func (a *TodoUsecase) Merge(c context.Context, id int64, apply func(models.User_todo_list) (models.User_todo_list, []string, error)) (models.User_todo_list, error) {
+	var (
+		roles  map[int64]string
+		res    models.User_todo_list
+		fields []string
+	)
+	err := withinTx(c, a.tx, func(c context.Context) (err error) {
+		if roles, err = authorize(c, a.shareRepo, models.RoleEditor, id); err != nil {
+			return err
+		}
+		original, err := a.todoRepo.GetForUpdate(c, id)
+		if err != nil {
+			return err
+		}
+		original.Role = roles[id]
+		todo, changed, err := apply(original)
+		if err != nil {
+			return err
+		}
+		fields = changed
+		res, err = a.todoRepo.Patch(c, id, todo, fields)
+		return err
+	})
+	if err != nil {
+		return models.User_todo_list{}, err
+	}
+	if len(fields) > 0 {
+		a.publish(c, models.EventTodoUpdated, res)
+	}
+	res.Role = roles[id]
+	return res, nil
 }
 
 func (a *TodoUsecase) Delete(c context.Context, id int64) error {
-	err := a.todoRepo.Delete(c, id)
+	err := withinTx(c, a.tx, func(c context.Context) error {
+		if _, err := authorize(c, a.shareRepo, models.RoleOwner, id); err != nil {
+			return err
+		}
+		return a.todoRepo.Delete(c, id)
+	})
 	if err != nil {
 		return err
 	}
+	a.publish(c, models.EventTodoDeleted, models.User_todo_list{ID: id})
 	return nil
 }
+
+// Watch streams todo events published by this instance until c is done.
+// Users only receive events of todos they can view; as a deleted todo has
+// no roles left, its deletion reaches the users that saw it on this stream.
+func (a *TodoUsecase) Watch(c context.Context) <-chan models.Todo_event {
+	events := a.broker.Subscribe(c)
+	user, ok := handler.UserFromContext(c)
+	if !ok {
+		return events
+	}
+	out := make(chan models.Todo_event, cap(events))
+	go func() {
+		defer close(out)
+		seen := map[int64]bool{}
+		for ev := range events {
+			id := ev.Data.ID
+			if ev.Event == models.EventTodoDeleted {
+				if !seen[id] {
+					continue
+				}
+				delete(seen, id)
+			} else {
+				roles, err := a.shareRepo.Roles(c, user, []int64{id})
+				if err != nil || roles[id] == "" {
+					continue
+				}
+				seen[id] = true
+				ev.Data.Role = roles[id]
+			}
+			select {
+			case out <- ev:
+			default:
+			}
+		}
+	}()
+	return out
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/KennyKur/CRUD_Todo/handler"
 	"github.com/KennyKur/CRUD_Todo/models"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -89,6 +90,20 @@ func TestTodoUsecase_Fetch(t *testing.T) {
 	}
 }
 
+func TestTodoUsecase_FetchPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodos := NewMockTodoRepositoryInterface(ctrl)
+	a := &TodoUsecase{todoRepo: mockTodos}
+
+	page := models.Todo_page{Todos: []models.User_todo_list{{ID: 3, Task_name: "daily"}}, Total: 3}
+	mockTodos.EXPECT().FetchPage(gomock.Any(), models.Todo_filter{}, 2, 2).Return(page, nil)
+	got, err := a.FetchPage(context.Background(), models.Todo_filter{}, 2, 2)
+	if err != nil || !reflect.DeepEqual(got, page) {
+		t.Errorf("TodoUsecase.FetchPage() = %v, %v, want %v", got, err, page)
+	}
+}
+
 func TestTodoUsecase_GetByID(t *testing.T) {
 	mockTodo := models.User_todo_list{ID: 4, Task_name: "daily"}
 	mockTodoErr := models.User_todo_list{ID: 0, Task_name: ""}
@@ -183,6 +198,7 @@ func TestTodoUsecase_Create(t *testing.T) {
 		fields  fields
 		args    args
 		mockFN  func(args)
+		wantRes models.User_todo_list
 		wantErr bool
 	}{
 		{
@@ -197,8 +213,9 @@ func TestTodoUsecase_Create(t *testing.T) {
 			mockFN: func(a args) {
 				mockUC.EXPECT().
 					Create(a.c, a.todo).
-					Return(nil)
+					Return(a.todo, nil)
 			},
+			wantRes: mockTodo,
 			wantErr: false,
 		},
 		{
@@ -213,8 +230,9 @@ func TestTodoUsecase_Create(t *testing.T) {
 			mockFN: func(a args) {
 				mockUC.EXPECT().
 					Create(a.c, a.todo).
-					Return(errors.New("Task tidak valid"))
+					Return(models.User_todo_list{}, errors.New("Task tidak valid"))
 			},
+			wantRes: models.User_todo_list{},
 			wantErr: true,
 		},
 	}
@@ -224,13 +242,64 @@ func TestTodoUsecase_Create(t *testing.T) {
 			a := &TodoUsecase{
 				todoRepo: tt.fields.todoRepo,
 			}
-			if err := a.Create(tt.args.c, tt.args.todo); (err != nil) != tt.wantErr {
+			gotRes, err := a.Create(tt.args.c, tt.args.todo)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("TodoUsecase.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("TodoUsecase.Create() = %v, want %v", gotRes, tt.wantRes)
 			}
 		})
 	}
 }
 
+func TestTodoUsecase_CreateQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockTodoRepositoryInterface(ctrl)
+	mockQuota := NewMockQuota(ctrl)
+	ctx := handler.WithUser(context.Background(), "kenny")
+	todo := models.User_todo_list{Task_name: "daily", Owner: "kenny"}
+
+	refunds := 0
+	refund := func() { refunds++ }
+	gomock.InOrder(
+		mockQuota.EXPECT().Reserve(ctx, "default|user:kenny").Return(refund, nil),
+		mockRepo.EXPECT().Create(ctx, todo).Return(models.User_todo_list{}, models.ErrInvalidTask),
+		mockQuota.EXPECT().Reserve(ctx, "default|user:kenny").
+			Return(nil, &models.QuotaError{Limit: 1}),
+	)
+
+	a := &TodoUsecase{todoRepo: mockRepo, quota: mockQuota}
+	if _, err := a.Create(ctx, models.User_todo_list{Task_name: "daily"}); !errors.Is(err, models.ErrInvalidTask) {
+		t.Errorf("TodoUsecase.Create() error = %v, want %v", err, models.ErrInvalidTask)
+	}
+	if refunds != 1 {
+		t.Errorf("refunds = %d, want the failed create refunded", refunds)
+	}
+	if _, err := a.Create(ctx, models.User_todo_list{Task_name: "daily"}); !errors.Is(err, models.ErrQuotaExceeded) {
+		t.Errorf("TodoUsecase.Create() error = %v, want %v", err, models.ErrQuotaExceeded)
+	}
+}
+
+func TestTodoUsecase_CreateQuotaPerClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockTodoRepositoryInterface(ctrl)
+	mockQuota := NewMockQuota(ctrl)
+	a := &TodoUsecase{todoRepo: mockRepo, quota: mockQuota}
+
+	for _, client := range []string{"key:7", "ip:10.0.0.1"} {
+		ctx := handler.WithClient(context.Background(), client)
+		mockQuota.EXPECT().Reserve(ctx, "default|"+client).Return(func() {}, nil)
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(models.User_todo_list{ID: 1, Task_name: "daily"}, nil)
+		if _, err := a.Create(ctx, models.User_todo_list{Task_name: "daily"}); err != nil {
+			t.Errorf("TodoUsecase.Create() as %s error = %v", client, err)
+		}
+	}
+}
+
 func TestTodoUsecase_Update(t *testing.T) {
 	mockTodo := models.User_todo_list{Task_name: "mengerjakan nxt"}
 	ctrl := gomock.NewController(t)
@@ -246,11 +315,13 @@ func TestTodoUsecase_Update(t *testing.T) {
 		id   int64
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		mockFN  func(args)
-		wantErr bool
+		name      string
+		fields    fields
+		args      args
+		mockFN    func(args)
+		wantRes   models.User_todo_list
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "success to update data",
@@ -265,8 +336,9 @@ func TestTodoUsecase_Update(t *testing.T) {
 			mockFN: func(a args) {
 				mockUC.EXPECT().
 					Update(a.c, a.todo, a.id).
-					Return(nil)
+					Return(models.User_todo_list{ID: a.id, Task_name: a.todo.Task_name}, nil)
 			},
+			wantRes: models.User_todo_list{ID: 4, Task_name: mockTodo.Task_name},
 			wantErr: false,
 		},
 		{
@@ -282,10 +354,28 @@ func TestTodoUsecase_Update(t *testing.T) {
 			mockFN: func(a args) {
 				mockUC.EXPECT().
 					Update(a.c, a.todo, a.id).
-					Return(errors.New("data not found"))
+					Return(models.User_todo_list{}, errors.New("data not found"))
 			},
 			wantErr: true,
 		},
+		{
+			name: "missing todo",
+			fields: fields{
+				todoRepo: mockUC,
+			},
+			args: args{
+				c:    context.Background(),
+				todo: mockTodo,
+				id:   999,
+			},
+			mockFN: func(a args) {
+				mockUC.EXPECT().
+					Update(a.c, a.todo, a.id).
+					Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: models.ErrNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -293,9 +383,64 @@ func TestTodoUsecase_Update(t *testing.T) {
 			a := &TodoUsecase{
 				todoRepo: tt.fields.todoRepo,
 			}
-			if err := a.Update(tt.args.c, tt.args.todo, tt.args.id); (err != nil) != tt.wantErr {
+			gotRes, err := a.Update(tt.args.c, tt.args.todo, tt.args.id)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("TodoUsecase.Update() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("TodoUsecase.Update() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if !reflect.DeepEqual(gotRes, tt.wantRes) {
+				t.Errorf("TodoUsecase.Update() = %v, want %v", gotRes, tt.wantRes)
+			}
+		})
+	}
+}
+
+func TestTodoUsecase_Patch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockTodoRepositoryInterface(ctrl)
+	todo := models.User_todo_list{ID: 4, Task_name: "mengerjakan nxt"}
+
+	tests := []struct {
+		name    string
+		fields  []string
+		mockFN  func()
+		wantRes models.User_todo_list
+		wantErr bool
+	}{
+		{
+			name:   "success to patch data",
+			fields: []string{"task_name"},
+			mockFN: func() {
+				mockRepo.EXPECT().Patch(gomock.Any(), int64(4), todo, []string{"task_name"}).Return(todo, nil)
+			},
+			wantRes: todo,
+		},
+		{
+			name:   "failed to patch data",
+			fields: []string{"task_name"},
+			mockFN: func() {
+				mockRepo.EXPECT().Patch(gomock.Any(), int64(4), todo, []string{"task_name"}).Return(models.User_todo_list{}, models.ErrNotFound)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &TodoUsecase{
+				todoRepo: mockRepo,
+			}
+			got, err := a.Patch(context.Background(), 4, todo, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TodoUsecase.Patch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("TodoUsecase.Patch() = %v, want %v", got, tt.wantRes)
+			}
 		})
 	}
 }
@@ -313,11 +458,12 @@ func TestTodoUsecase_Delete(t *testing.T) {
 		id int64
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		mockFn  func(args)
-		wantErr bool
+		name      string
+		fields    fields
+		args      args
+		mockFn    func(args)
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "success to delete data",
@@ -351,6 +497,23 @@ func TestTodoUsecase_Delete(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "missing todo",
+			fields: fields{
+				todoRepo: mockUC,
+			},
+			args: args{
+				c:  context.Background(),
+				id: 999,
+			},
+			mockFn: func(a args) {
+				mockUC.EXPECT().
+					Delete(a.c, a.id).
+					Return(models.ErrNotFound)
+			},
+			wantErr:   true,
+			wantErrIs: models.ErrNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -358,9 +521,13 @@ func TestTodoUsecase_Delete(t *testing.T) {
 			a := &TodoUsecase{
 				todoRepo: tt.fields.todoRepo,
 			}
-			if err := a.Delete(tt.args.c, tt.args.id); (err != nil) != tt.wantErr {
+			err := a.Delete(tt.args.c, tt.args.id)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("TodoUsecase.Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("TodoUsecase.Delete() error = %v, want %v", err, tt.wantErrIs)
+			}
 		})
 	}
 }
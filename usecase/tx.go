@@ -0,0 +1,13 @@
+package usecase
+
+import "context"
+
+// withinTx runs fn as a unit of work of tx, or on its own when tx is nil.
+// Events must be published after it returns, as fn may be retried and its
+// writes rolled back.
+func withinTx(c context.Context, tx Transactor, fn func(c context.Context) error) error {
+	if tx == nil {
+		return fn(c)
+	}
+	return tx.WithinTx(c, fn)
+}
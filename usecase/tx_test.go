@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+type txKey struct{}
+
+// runInTx expects one unit of work and marks the context it hands to fn, so
+// repository calls can be checked to be part of it.
+func runInTx(tx *MockTransactor) {
+	tx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(context.WithValue(ctx, txKey{}, true))
+		})
+}
+
+// inTx matches contexts handed out by runInTx.
+var inTx gomock.Matcher = txMatcher{}
+
+type txMatcher struct{}
+
+func (txMatcher) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && ctx.Value(txKey{}) != nil
+}
+
+func (txMatcher) String() string { return "is a context inside the unit of work" }
+
+func TestTodoUsecase_UnitOfWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodos := NewMockTodoRepositoryInterface(ctrl)
+	mockShares := NewMockShareRepositoryInterface(ctrl)
+	mockTx := NewMockTransactor(ctrl)
+	a := NewTodoUsecase(mockTodos, mockShares, mockTx, nil).(*TodoUsecase)
+
+	ctx, cancel := context.WithCancel(handler.WithUser(context.Background(), "budi"))
+	defer cancel()
+	events := a.broker.Subscribe(ctx)
+
+	runInTx(mockTx)
+	mockShares.EXPECT().Roles(inTx, "budi", []int64{1}).Return(map[int64]string{1: models.RoleOwner}, nil)
+	mockTodos.EXPECT().Delete(inTx, int64(1)).Return(nil)
+	if err := a.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if ev := <-events; ev.Event != models.EventTodoDeleted {
+		t.Errorf("event = %q, want %q", ev.Event, models.EventTodoDeleted)
+	}
+
+	errDB := errors.New("some error")
+	runInTx(mockTx)
+	mockShares.EXPECT().Roles(inTx, "budi", []int64{2}).Return(map[int64]string{2: models.RoleEditor}, nil)
+	mockTodos.EXPECT().Update(inTx, gomock.Any(), int64(2)).Return(models.User_todo_list{}, errDB)
+	if _, err := a.Update(ctx, models.User_todo_list{Task_name: "Belajar"}, 2); !errors.Is(err, errDB) {
+		t.Fatalf("Update() error = %v, want %v", err, errDB)
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("failed update published %q", ev.Event)
+	default:
+	}
+}
+
+func TestTodoUsecase_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTodos := NewMockTodoRepositoryInterface(ctrl)
+	mockShares := NewMockShareRepositoryInterface(ctrl)
+	mockTx := NewMockTransactor(ctrl)
+	a := NewTodoUsecase(mockTodos, mockShares, mockTx, nil).(*TodoUsecase)
+	ctx := handler.WithUser(context.Background(), "budi")
+	stored := models.User_todo_list{ID: 2, Task_name: "daily", Owner: "siti"}
+	rename := func(todo models.User_todo_list) (models.User_todo_list, []string, error) {
+		if todo.Role != models.RoleEditor {
+			t.Errorf("apply() got role %q, want %q", todo.Role, models.RoleEditor)
+		}
+		todo.Task_name = "Belajar"
+		return todo, []string{"task_name"}, nil
+	}
+
+	runInTx(mockTx)
+	mockShares.EXPECT().Roles(inTx, "budi", []int64{2}).Return(map[int64]string{2: models.RoleEditor}, nil)
+	mockTodos.EXPECT().GetForUpdate(inTx, int64(2)).Return(stored, nil)
+	mockTodos.EXPECT().Patch(inTx, int64(2), models.User_todo_list{ID: 2, Task_name: "Belajar", Owner: "siti", Role: models.RoleEditor}, []string{"task_name"}).
+		Return(models.User_todo_list{ID: 2, Task_name: "Belajar", Owner: "siti"}, nil)
+	got, err := a.Merge(ctx, 2, rename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.User_todo_list{ID: 2, Task_name: "Belajar", Owner: "siti", Role: models.RoleEditor}); got != want {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+
+	errPatch := errors.New("task_name tidak boleh kosong")
+	runInTx(mockTx)
+	mockShares.EXPECT().Roles(inTx, "budi", []int64{2}).Return(map[int64]string{2: models.RoleEditor}, nil)
+	mockTodos.EXPECT().GetForUpdate(inTx, int64(2)).Return(stored, nil)
+	_, err = a.Merge(ctx, 2, func(todo models.User_todo_list) (models.User_todo_list, []string, error) {
+		return todo, nil, errPatch
+	})
+	if !errors.Is(err, errPatch) {
+		t.Fatalf("Merge() error = %v, want %v", err, errPatch)
+	}
+}
+
+func TestShareUsecase_UnitOfWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShareRepositoryInterface(ctrl)
+	mockTx := NewMockTransactor(ctrl)
+	a := NewShareUsecase(mockRepo, mockTx)
+	ctx := handler.WithUser(context.Background(), "budi")
+
+	runInTx(mockTx)
+	mockRepo.EXPECT().Roles(inTx, "budi", []int64{1}).Return(map[int64]string{1: models.RoleOwner}, nil)
+	mockRepo.EXPECT().Create(inTx, gomock.Any()).Return([]models.Todo_share{{Todo_id: 1, User: "siti"}}, nil)
+	if _, err := a.Share(ctx, []int64{1}, "siti", models.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+
+	runInTx(mockTx)
+	mockRepo.EXPECT().Roles(inTx, "budi", []int64{1}).Return(map[int64]string{1: models.RoleOwner}, nil)
+	mockRepo.EXPECT().Delete(inTx, int64(1), "siti").Return(nil)
+	if err := a.Revoke(ctx, 1, "siti"); err != nil {
+		t.Fatal(err)
+	}
+}
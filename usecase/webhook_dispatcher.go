@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/logging"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookDispatcher drains the webhook outbox, signing and posting each
+// delivery and rescheduling failures with exponential backoff until they
+// exceed MaxAttempts and are moved to the dead-letter state.
+type WebhookDispatcher struct {
+	webhookRepo WebhookRepositoryInterface
+	Client      *http.Client
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+	now         func() time.Time
+}
+
+func NewWebhookDispatcher(a WebhookRepositoryInterface, timeout time.Duration, maxAttempts int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo: a,
+		Client:      &http.Client{Timeout: timeout},
+		MaxAttempts: maxAttempts,
+		BaseBackoff: 10 * time.Second,
+		MaxBackoff:  time.Hour,
+		BatchSize:   50,
+		now:         time.Now,
+	}
+}
+
+// SignPayload returns the signature header value for body sent at ts:
+// "t=<unix>,v1=<hex hmac-sha256(secret, "<unix>.<body>")>".
+func SignPayload(secret string, ts time.Time, body []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the given (1-based) retry attempt.
+func (d *WebhookDispatcher) Backoff(attempt int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Run dispatches due deliveries every interval until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := d.DispatchPending(ctx); err != nil {
+			logging.FromContext(ctx).Error("webhook dispatch failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) DispatchPending(ctx context.Context) error {
+	now := d.now()
+	// deliveries are sent one after another, so the lease has to outlast
+	// a batch in which every send runs into the timeout
+	lease := now.Add(time.Duration(d.BatchSize)*d.Client.Timeout + d.BaseBackoff)
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, now, lease, d.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		delivery = d.attempt(ctx, delivery)
+		if err := d.webhookRepo.SaveAttempt(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery models.Webhook_delivery) models.Webhook_delivery {
+	delivery.Attempts++
+	delivery.Next_attempt_at = d.now()
+	err := d.send(ctx, delivery)
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.Last_error = ""
+		return delivery
+	}
+	delivery.Last_error = err.Error()
+	if delivery.Attempts >= d.MaxAttempts {
+		delivery.Status = models.DeliveryDead
+		return delivery
+	}
+	delivery.Status = models.DeliveryPending
+	delivery.Next_attempt_at = delivery.Next_attempt_at.Add(d.Backoff(delivery.Attempts))
+	return delivery
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.Webhook_delivery) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event_type)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, SignPayload(delivery.Secret, d.now(), body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestSignPayload(t *testing.T) {
+	ts := time.Unix(1650000000, 0)
+	got := SignPayload("s3cret", ts, []byte(`{"event":"todo.created"}`))
+	want := "t=1650000000,v1=91c73bf582b682c74598db583040399bb89427a3eae55975088e9a92b3a348f3"
+	if got != want {
+		t.Errorf("SignPayload() = %v, want %v", got, want)
+	}
+}
+
+func TestWebhookDispatcher_Backoff(t *testing.T) {
+	d := &WebhookDispatcher{BaseBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{10, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := d.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDispatcher_DispatchPending(t *testing.T) {
+	now := time.Unix(1650000000, 0)
+	payload := `{"event":"todo.created","data":{"id":1,"task_name":"Belajar"}}`
+
+	tests := []struct {
+		name       string
+		status     int
+		attempts   int
+		wantStatus string
+		wantNext   time.Time
+	}{
+		{
+			name:       "delivered",
+			status:     http.StatusNoContent,
+			attempts:   0,
+			wantStatus: models.DeliveryDelivered,
+			wantNext:   now,
+		},
+		{
+			name:       "failed and rescheduled",
+			status:     http.StatusInternalServerError,
+			attempts:   1,
+			wantStatus: models.DeliveryPending,
+			wantNext:   now.Add(20 * time.Second),
+		},
+		{
+			name:       "failed and dead-lettered",
+			status:     http.StatusBadGateway,
+			attempts:   2,
+			wantStatus: models.DeliveryDead,
+			wantNext:   now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				if string(body) != payload {
+					t.Errorf("body = %s, want %s", body, payload)
+				}
+				if got, want := r.Header.Get(SignatureHeader), SignPayload("s3cret", now, body); got != want {
+					t.Errorf("signature = %s, want %s", got, want)
+				}
+				if r.Header.Get(EventHeader) != models.EventTodoCreated {
+					t.Errorf("event header = %s", r.Header.Get(EventHeader))
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := NewMockWebhookRepositoryInterface(ctrl)
+
+			d := NewWebhookDispatcher(mockRepo, time.Second, 3)
+			d.now = func() time.Time { return now }
+
+			delivery := models.Webhook_delivery{
+				ID:         1,
+				Url:        srv.URL,
+				Secret:     "s3cret",
+				Event_type: models.EventTodoCreated,
+				Payload:    payload,
+				Status:     models.DeliveryPending,
+				Attempts:   tt.attempts,
+			}
+			mockRepo.EXPECT().
+				ClaimDueDeliveries(gomock.Any(), now, now.Add(50*time.Second+d.BaseBackoff), d.BatchSize).
+				Return([]models.Webhook_delivery{delivery}, nil)
+			mockRepo.EXPECT().
+				SaveAttempt(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, got models.Webhook_delivery) error {
+					if got.Status != tt.wantStatus {
+						t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+					}
+					if got.Attempts != tt.attempts+1 {
+						t.Errorf("attempts = %d, want %d", got.Attempts, tt.attempts+1)
+					}
+					if !got.Next_attempt_at.Equal(tt.wantNext) {
+						t.Errorf("next attempt = %v, want %v", got.Next_attempt_at, tt.wantNext)
+					}
+					return nil
+				})
+
+			if err := d.DispatchPending(context.Background()); err != nil {
+				t.Errorf("WebhookDispatcher.DispatchPending() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"github.com/KennyKur/CRUD_Todo/handler"
+	"github.com/KennyKur/CRUD_Todo/models"
+)
+
+type WebhookUsecase struct {
+	webhookRepo WebhookRepositoryInterface
+}
+
+func NewWebhookUsecase(a WebhookRepositoryInterface) handler.WebhookUsecaseInterface {
+	return &WebhookUsecase{
+		webhookRepo: a,
+	}
+}
+
+func validateWebhook(sub models.Webhook_subscription) error {
+	u, err := url.Parse(sub.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url tidak valid")
+	}
+	if len(sub.Event_types) == 0 {
+		return errors.New("event tidak valid")
+	}
+	for _, e := range sub.Event_types {
+		valid := false
+		for _, known := range models.WebhookEvents {
+			if e == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return errors.New("event tidak valid")
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (a *WebhookUsecase) Fetch(c context.Context) (res []models.Webhook_subscription, err error) {
+	res, err = a.webhookRepo.Fetch(c)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (a *WebhookUsecase) GetByID(c context.Context, id int64) (res models.Webhook_subscription, err error) {
+	res, err = a.webhookRepo.GetByID(c, id)
+	return
+}
+
+// Create stores a new subscription. The returned subscription carries the
+// signing secret; it is not exposed again afterwards.
+func (a *WebhookUsecase) Create(c context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error) {
+	if err := validateWebhook(sub); err != nil {
+		return models.Webhook_subscription{}, err
+	}
+	if sub.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return models.Webhook_subscription{}, err
+		}
+		sub.Secret = secret
+	}
+	sub.Active = true
+	return a.webhookRepo.Create(c, sub)
+}
+
+func (a *WebhookUsecase) Update(c context.Context, sub models.Webhook_subscription, id int64) error {
+	if err := validateWebhook(sub); err != nil {
+		return err
+	}
+	return a.webhookRepo.Update(c, sub, id)
+}
+
+func (a *WebhookUsecase) Delete(c context.Context, id int64) error {
+	return a.webhookRepo.Delete(c, id)
+}
+
+func (a *WebhookUsecase) FetchDeadDeliveries(c context.Context) ([]models.Webhook_delivery, error) {
+	return a.webhookRepo.FetchDeadDeliveries(c)
+}
+
+func (a *WebhookUsecase) RetryDelivery(c context.Context, id int64) error {
+	return a.webhookRepo.Requeue(c, id)
+}
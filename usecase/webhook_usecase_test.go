@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KennyKur/CRUD_Todo/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+func TestWebhookUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockWebhookRepositoryInterface(ctrl)
+
+	valid := models.Webhook_subscription{
+		Url:         "https://example.com/hook",
+		Event_types: []string{models.EventTodoCreated, models.EventTodoDeleted},
+	}
+	tests := []struct {
+		name    string
+		sub     models.Webhook_subscription
+		mockFN  func()
+		wantErr bool
+	}{
+		{
+			name: "success to add subscription",
+			sub:  valid,
+			mockFN: func() {
+				mockRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, sub models.Webhook_subscription) (models.Webhook_subscription, error) {
+						if len(sub.Secret) != 64 || !sub.Active {
+							t.Errorf("expected generated secret and active subscription, got %+v", sub)
+						}
+						sub.ID = 1
+						return sub, nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid url",
+			sub:     models.Webhook_subscription{Url: "ftp://example.com", Event_types: valid.Event_types},
+			mockFN:  func() {},
+			wantErr: true,
+		},
+		{
+			name:    "unknown event",
+			sub:     models.Webhook_subscription{Url: valid.Url, Event_types: []string{"todo.archived"}},
+			mockFN:  func() {},
+			wantErr: true,
+		},
+		{
+			name: "failed to add subscription",
+			sub:  valid,
+			mockFN: func() {
+				mockRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(models.Webhook_subscription{}, errors.New("gagal menyimpan data"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFN()
+			a := &WebhookUsecase{
+				webhookRepo: mockRepo,
+			}
+			if _, err := a.Create(context.Background(), tt.sub); (err != nil) != tt.wantErr {
+				t.Errorf("WebhookUsecase.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}